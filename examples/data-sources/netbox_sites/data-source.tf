@@ -0,0 +1,10 @@
+data "netbox_sites" "active_edge_pops" {
+  filter {
+    name  = "group"
+    value = "edge-pops"
+  }
+  filter {
+    name  = "status"
+    value = "active"
+  }
+}
@@ -0,0 +1,5 @@
+data "netbox_status" "this" {}
+
+output "netbox_version" {
+  value = data.netbox_status.this.netbox_version
+}
@@ -0,0 +1,11 @@
+resource "netbox_cable" "uplink" {
+  termination_a_type = "dcim.interface"
+  termination_a_id   = netbox_device_interface.uplink.id
+  termination_b_type = "circuits.circuittermination"
+  termination_b_id   = netbox_circuit_termination.a_side.id
+  type               = "smf-os2"
+  color_hex          = "ffff00"
+  length             = 15
+  length_unit        = "m"
+  label              = "XC-1042"
+}
@@ -0,0 +1,5 @@
+# Import by numeric ID
+terraform import netbox_device_interface.example 12
+
+# Import by the name of the device and the name of the interface
+terraform import netbox_device_interface.example sw01/Ethernet1/1
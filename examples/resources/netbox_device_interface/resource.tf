@@ -0,0 +1,9 @@
+resource "netbox_device_interface" "uplink" {
+  name             = "Ethernet1/1"
+  device_id        = netbox_device.sw01.id
+  type             = "10gbase-x-sfpp"
+  mtu              = 9000
+  mode             = "tagged"
+  untagged_vlan_id = netbox_vlan.native.id
+  tagged_vlans     = [netbox_vlan.servers.id, netbox_vlan.storage.id]
+}
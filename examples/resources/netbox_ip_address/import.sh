@@ -0,0 +1,8 @@
+# Import by numeric ID
+terraform import netbox_ip_address.example 12
+
+# Import by address, which must be unique across all VRFs
+terraform import netbox_ip_address.example 10.0.0.1/24
+
+# Import by address within a VRF
+terraform import netbox_ip_address.example 10.0.0.1/24@production
@@ -0,0 +1,5 @@
+# Import by numeric ID
+terraform import netbox_ipam_role.example 12
+
+# Import by slug
+terraform import netbox_ipam_role.example production
@@ -0,0 +1,5 @@
+# Import by numeric ID
+terraform import netbox_location.example 12
+
+# Import by slug, optionally followed by the slug of the site
+terraform import netbox_location.example floor-1@dc1
@@ -0,0 +1,16 @@
+resource "netbox_site" "dc1" {
+  name = "dc1"
+}
+
+resource "netbox_location" "building_a" {
+  name    = "Building A"
+  slug    = "building-a"
+  site_id = netbox_site.dc1.id
+}
+
+resource "netbox_location" "floor_1" {
+  name      = "Floor 1"
+  slug      = "floor-1"
+  site_id   = netbox_site.dc1.id
+  parent_id = netbox_location.building_a.id
+}
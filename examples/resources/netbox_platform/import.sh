@@ -0,0 +1,5 @@
+# Import by numeric ID
+terraform import netbox_platform.example 12
+
+# Import by slug
+terraform import netbox_platform.example ubuntu
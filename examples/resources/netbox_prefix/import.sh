@@ -0,0 +1,8 @@
+# Import by numeric ID
+terraform import netbox_prefix.example 12
+
+# Import by prefix, which must be unique across all VRFs
+terraform import netbox_prefix.example 10.0.0.0/24
+
+# Import by prefix within a VRF
+terraform import netbox_prefix.example 10.0.0.0/24@production
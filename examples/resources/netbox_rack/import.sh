@@ -0,0 +1,2 @@
+# Import by numeric ID
+terraform import netbox_rack.example 12
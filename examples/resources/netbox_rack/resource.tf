@@ -0,0 +1,15 @@
+resource "netbox_site" "dc1" {
+  name = "dc1"
+}
+
+resource "netbox_rack_role" "compute" {
+  name      = "compute"
+  color_hex = "00FF00"
+}
+
+resource "netbox_rack" "r01" {
+  name     = "R01"
+  site_id  = netbox_site.dc1.id
+  role_id  = netbox_rack_role.compute.id
+  u_height = 48
+}
@@ -0,0 +1,4 @@
+resource "netbox_rack_role" "compute" {
+  name      = "compute"
+  color_hex = "00FF00"
+}
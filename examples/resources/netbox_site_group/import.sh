@@ -0,0 +1,5 @@
+# Import by numeric ID
+terraform import netbox_site_group.example 12
+
+# Import by slug
+terraform import netbox_site_group.example edge-pops
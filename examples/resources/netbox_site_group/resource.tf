@@ -0,0 +1,8 @@
+resource "netbox_site_group" "pops" {
+  name = "pops"
+}
+
+resource "netbox_site_group" "edge_pops" {
+  name      = "edge-pops"
+  parent_id = netbox_site_group.pops.id
+}
@@ -0,0 +1,8 @@
+# Import by numeric ID
+terraform import netbox_virtual_machine.example 12
+
+# Import by name, which must be unique
+terraform import netbox_virtual_machine.example web01
+
+# Import by name within a cluster
+terraform import netbox_virtual_machine.example web01@production-cluster
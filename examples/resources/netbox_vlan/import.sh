@@ -0,0 +1,8 @@
+# Import by numeric ID
+terraform import netbox_vlan.example 12
+
+# Import by VLAN ID, which must be unique
+terraform import netbox_vlan.example vid:100
+
+# Import by VLAN ID within a site, identified by its slug
+terraform import netbox_vlan.example 100@dc-frankfurt
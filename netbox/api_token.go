@@ -0,0 +1,154 @@
+package netbox
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// apiTokenSource holds the API token sent to Netbox. Tokens read from a file
+// or returned by a command can be reloaded, e.g. after they were rotated.
+type apiTokenSource struct {
+	mu    sync.Mutex
+	token string
+	// load returns the current token. It is nil for static tokens.
+	load func() (string, error)
+}
+
+// get returns the current token.
+func (s *apiTokenSource) get() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// reload loads the token again if the token that was rejected is still the
+// current one. It returns true if the current token differs from the rejected
+// one, i.e. if it makes sense to send the request again.
+func (s *apiTokenSource) reload(rejected string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != rejected {
+		// Another request already reloaded the token
+		return true, nil
+	}
+	if s.load == nil {
+		return false, nil
+	}
+
+	token, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	s.token = token
+	return token != rejected, nil
+}
+
+// apiTokenFromFile returns a function reading the API token from the given
+// file. Surrounding whitespace is removed.
+func apiTokenFromFile(path string) func() (string, error) {
+	return func() (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("Error while trying to read API token file: %s", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("API token file %s is empty", path)
+		}
+		return token, nil
+	}
+}
+
+// apiTokenFromCommand returns a function running the given command with the
+// system shell and returning its output as API token. Surrounding whitespace
+// is removed.
+func apiTokenFromCommand(command string) func() (string, error) {
+	return func() (string, error) {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", command)
+		} else {
+			cmd = exec.Command("sh", "-c", command)
+		}
+
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		output, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("Error while running API token command: %s: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		token := strings.TrimSpace(string(output))
+		if token == "" {
+			return "", fmt.Errorf("API token command did not return a token")
+		}
+		return token, nil
+	}
+}
+
+// apiTokenTransport is a transport that authenticates every request with the
+// current API token. If Netbox rejects the token, it is reloaded and the
+// request is sent once more with the new token.
+type apiTokenTransport struct {
+	original http.RoundTripper
+	source   *apiTokenSource
+}
+
+// RoundTrip sends the request with the current API token.
+func (t apiTokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	token := t.source.get()
+
+	resp, err := t.original.RoundTrip(withAPIToken(r, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// A request body that cannot be rewound can only be sent once
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return resp, nil
+	}
+
+	changed, reloadErr := t.source.reload(token)
+	if reloadErr != nil {
+		log.WithFields(log.Fields{
+			"error": reloadErr,
+		}).Warn("Unable to reload the Netbox API token")
+		return resp, nil
+	}
+	if !changed {
+		return resp, nil
+	}
+
+	log.Debug("Netbox rejected the API token, retrying with the reloaded token")
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	req := withAPIToken(r, t.source.get())
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+	return t.original.RoundTrip(req)
+}
+
+// withAPIToken returns a copy of the request with the Authorization header
+// set to the given token.
+func withAPIToken(r *http.Request, token string) *http.Request {
+	req := r.Clone(r.Context())
+	req.Header.Set("Authorization", fmt.Sprintf("Token %v", token))
+	return req
+}
@@ -0,0 +1,102 @@
+package netbox
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITokenFromFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(path, []byte("07b12b765127747e4afd56cb531b7bf9c61f3c30\n"), 0600))
+
+	token, err := apiTokenFromFile(path)()
+	assert.NoError(t, err)
+	assert.Equal(t, "07b12b765127747e4afd56cb531b7bf9c61f3c30", token)
+
+	assert.NoError(t, os.WriteFile(path, []byte("  \n"), 0600))
+	_, err = apiTokenFromFile(path)()
+	assert.Error(t, err)
+
+	_, err = apiTokenFromFile(filepath.Join(t.TempDir(), "missing"))()
+	assert.Error(t, err)
+}
+
+func TestAPITokenFromCommand(t *testing.T) {
+
+	token, err := apiTokenFromCommand("echo 07b12b765127747e4afd56cb531b7bf9c61f3c30")()
+	assert.NoError(t, err)
+	assert.Equal(t, "07b12b765127747e4afd56cb531b7bf9c61f3c30", token)
+
+	_, err = apiTokenFromCommand("exit 1")()
+	assert.Error(t, err)
+}
+
+func TestAPITokenTransportReloadsRejectedToken(t *testing.T) {
+
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"name":"foo"}`, string(body))
+
+		if r.Header.Get("Authorization") != "Token new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(path, []byte("new"), 0600))
+
+	client := &http.Client{
+		Transport: apiTokenTransport{
+			original: http.DefaultTransport,
+			source:   &apiTokenSource{token: "old", load: apiTokenFromFile(path)},
+		},
+	}
+
+	resp, err := client.Post(ts.URL, "application/json", strings.NewReader(`{"name":"foo"}`))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestAPITokenTransportDoesNotRetryUnchangedToken(t *testing.T) {
+
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		assert.Equal(t, "Token old", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	for _, source := range []*apiTokenSource{
+		{token: "old"},
+		{token: "old", load: func() (string, error) { return "old", nil }},
+	} {
+		atomic.StoreInt32(&requests, 0)
+
+		client := &http.Client{
+			Transport: apiTokenTransport{original: http.DefaultTransport, source: source},
+		}
+
+		resp, err := client.Get(ts.URL)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	}
+}
@@ -1,21 +1,51 @@
 package netbox
 
 import (
+	"context"
+	"crypto/x509"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
 
 	netboxclient "github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/go-openapi/runtime"
 	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/goware/urlx"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpproxy"
 )
 
 // Config struct for the netbox provider
 type Config struct {
-	APIToken           string
-	ServerURL          string
-	AllowInsecureHttps bool
-	Headers            map[string]interface{}
+	APIToken              string
+	APITokenLoader        func() (string, error)
+	ServerURL             string
+	AllowInsecureHttps    bool
+	CACertFile            string
+	CACertPEM             string
+	ClientCertFile        string
+	ClientKeyFile         string
+	HTTPProxy             string
+	NoProxy               string
+	RequestTimeout        time.Duration
+	MaxIdleConns          int
+	IdleConnTimeout       time.Duration
+	DisableKeepAlives     bool
+	DebugHTTP             bool
+	ReadOnly              bool
+	Headers               map[string]interface{}
+	MaxRetries            int
+	RetryWaitMin          time.Duration
+	RetryWaitMax          time.Duration
+	RequestsPerSecond     float64
+	MaxConcurrentRequests int
+	// LogContext carries the Terraform logger used for requests that are
+	// not made on behalf of a single resource.
+	LogContext context.Context
 }
 
 // customHeaderTransport is a transport that adds the specified headers on
@@ -25,6 +55,26 @@ type customHeaderTransport struct {
 	headers  map[string]interface{}
 }
 
+// backgroundContextTransport is a client transport that submits operations
+// without a context with a context that is never cancelled. The go-openapi
+// runtime aborts such operations after its default timeout, which would
+// override the timeout configured for the provider.
+type backgroundContextTransport struct {
+	runtime.ClientTransport
+	ctx context.Context
+}
+
+// detachedContext carries the values of its parent, e.g. the Terraform
+// logger, but is never cancelled.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
 // Client does the heavy lifting of establishing a base Open API client to Netbox.
 func (cfg *Config) Client() (interface{}, error) {
 
@@ -33,7 +83,7 @@ func (cfg *Config) Client() (interface{}, error) {
 	}).Debug("Initializing Netbox client")
 
 	if cfg.APIToken == "" {
-		return nil, fmt.Errorf("Missing netbox API key")
+		return nil, fmt.Errorf("Missing netbox API key, set one of api_token, api_token_file or api_token_command")
 	}
 
 	// parse serverUrl
@@ -51,16 +101,91 @@ func (cfg *Config) Client() (interface{}, error) {
 	// build http client
 	clientOpts := httptransport.TLSClientOptions{
 		InsecureSkipVerify: cfg.AllowInsecureHttps,
+		Certificate:        cfg.ClientCertFile,
+		Key:                cfg.ClientKeyFile,
+	}
+
+	if (cfg.ClientCertFile == "") != (cfg.ClientKeyFile == "") {
+		return nil, fmt.Errorf("Both a client certificate and a client key are required for TLS client authentication")
+	}
+
+	if cfg.CACertFile != "" || cfg.CACertPEM != "" {
+		caCertPool, err := loadCACertPool(cfg.CACertFile, cfg.CACertPEM)
+		if err != nil {
+			return nil, err
+		}
+		clientOpts.LoadedCAPool = caCertPool
 	}
 
-	trans, err := httptransport.TLSTransport(clientOpts)
+	tlsConfig, err := httptransport.TLSClientAuth(clientOpts)
 	if err != nil {
 		return nil, err
 	}
 
+	baseTransport := http.DefaultTransport.(*http.Transport).Clone()
+	baseTransport.TLSClientConfig = tlsConfig
+	baseTransport.DisableKeepAlives = cfg.DisableKeepAlives
+	if cfg.MaxIdleConns > 0 {
+		baseTransport.MaxIdleConns = cfg.MaxIdleConns
+		baseTransport.MaxIdleConnsPerHost = cfg.MaxIdleConns
+	}
+	if cfg.IdleConnTimeout > 0 {
+		baseTransport.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+
+	if cfg.HTTPProxy != "" {
+		log.WithFields(log.Fields{
+			"http_proxy": cfg.HTTPProxy,
+			"no_proxy":   cfg.NoProxy,
+		}).Debug("Sending requests to Netbox via proxy")
+
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  cfg.HTTPProxy,
+			HTTPSProxy: cfg.HTTPProxy,
+			NoProxy:    cfg.NoProxy,
+		}).ProxyFunc()
+		baseTransport.Proxy = func(r *http.Request) (*url.URL, error) {
+			return proxyFunc(r.URL)
+		}
+	}
+
+	var trans http.RoundTripper = baseTransport
+
+	if cfg.RequestTimeout > 0 {
+		trans = timeoutTransport{
+			original: trans,
+			timeout:  cfg.RequestTimeout,
+		}
+	}
+
+	if cfg.DebugHTTP || isTFLogDebugEnabled() {
+		secretHeaders := make(map[string]bool, len(cfg.Headers))
+		for key := range cfg.Headers {
+			secretHeaders[http.CanonicalHeaderKey(key)] = true
+		}
+
+		// Terraform only shows debug messages of providers with TF_LOG=DEBUG
+		logFunc := tflog.Debug
+		if cfg.DebugHTTP {
+			logFunc = tflog.Info
+		}
+
+		trans = traceTransport{
+			original:      trans,
+			secretHeaders: secretHeaders,
+			log:           logFunc,
+		}
+	}
+
 	if cfg.Headers != nil && len(cfg.Headers) > 0 {
+		headerNames := make([]string, 0, len(cfg.Headers))
+		for key := range cfg.Headers {
+			headerNames = append(headerNames, key)
+		}
+		sort.Strings(headerNames)
+
 		log.WithFields(log.Fields{
-			"custom_headers": cfg.Headers,
+			"custom_headers": headerNames,
 		}).Debug("Setting custom headers on every request to Netbox")
 
 		trans = customHeaderTransport{
@@ -69,25 +194,103 @@ func (cfg *Config) Client() (interface{}, error) {
 		}
 	}
 
+	if cfg.RequestsPerSecond > 0 || cfg.MaxConcurrentRequests > 0 {
+		log.WithFields(log.Fields{
+			"requests_per_second":     cfg.RequestsPerSecond,
+			"max_concurrent_requests": cfg.MaxConcurrentRequests,
+		}).Debug("Limiting requests to Netbox")
+
+		trans = newRateLimitTransport(trans, cfg.RequestsPerSecond, cfg.MaxConcurrentRequests)
+	}
+
+	if cfg.MaxRetries > 0 {
+		log.WithFields(log.Fields{
+			"max_retries":    cfg.MaxRetries,
+			"retry_wait_min": cfg.RetryWaitMin.String(),
+			"retry_wait_max": cfg.RetryWaitMax.String(),
+		}).Debug("Retrying requests to Netbox on transient errors")
+
+		trans = retryTransport{
+			original:   trans,
+			maxRetries: cfg.MaxRetries,
+			waitMin:    cfg.RetryWaitMin,
+			waitMax:    cfg.RetryWaitMax,
+		}
+	}
+
+	trans = apiTokenTransport{
+		original: trans,
+		source: &apiTokenSource{
+			token: cfg.APIToken,
+			load:  cfg.APITokenLoader,
+		},
+	}
+
+	if cfg.ReadOnly {
+		log.Debug("Refusing all requests that could modify Netbox")
+
+		trans = readOnlyTransport{
+			original: trans,
+		}
+	}
+
 	httpClient := &http.Client{
 		Transport: trans,
 	}
 
 	transport := httptransport.NewWithClient(parsedURL.Host, parsedURL.Path+netboxclient.DefaultBasePath, desiredRuntimeClientSchemes, httpClient)
-	transport.DefaultAuthentication = httptransport.APIKeyAuth("Authorization", "header", fmt.Sprintf("Token %v", cfg.APIToken))
 	transport.SetLogger(log.StandardLogger())
-	netboxClient := netboxclient.New(transport, nil)
+	logContext := cfg.LogContext
+	if logContext == nil {
+		logContext = context.Background()
+	}
+	netboxClient := netboxclient.New(backgroundContextTransport{ClientTransport: transport, ctx: detachedContext{logContext}}, nil)
 
 	return netboxClient, nil
 }
 
-// RoundTrip adds the headers specified in the transport on every request.
-func (t customHeaderTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+// Submit sends the operation, defaulting to the context of the transport.
+func (t backgroundContextTransport) Submit(operation *runtime.ClientOperation) (interface{}, error) {
+	if operation.Context == nil {
+		operation.Context = t.ctx
+	}
+	return t.ClientTransport.Submit(operation)
+}
 
+// RoundTrip adds the headers specified in the transport on every request. The
+// headers are set on a copy, as the request may be sent again on retries.
+func (t customHeaderTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	req := r.Clone(r.Context())
 	for key, value := range t.headers {
-		r.Header.Add(key, fmt.Sprintf("%v", value))
+		req.Header.Add(key, fmt.Sprintf("%v", value))
 	}
 
-	resp, err := t.original.RoundTrip(r)
+	resp, err := t.original.RoundTrip(req)
 	return resp, err
 }
+
+// loadCACertPool returns the system certificate pool extended by the PEM
+// encoded CA certificates read from caCertFile or given in caCertPEM.
+func loadCACertPool(caCertFile string, caCertPEM string) (*x509.CertPool, error) {
+	pemData := []byte(caCertPEM)
+	if caCertFile != "" {
+		data, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error while trying to read CA certificate file: %s", err)
+		}
+		pemData = data
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err,
+		}).Debug("Unable to load system certificate pool, using only the configured CA certificates")
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("No valid PEM encoded CA certificate found")
+	}
+	return pool, nil
+}
@@ -1,9 +1,20 @@
 package netbox
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	netboxClient "github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/status"
@@ -94,6 +105,231 @@ func TestAdditionalHeadersSet(t *testing.T) {
 	client.(*netboxClient.NetBoxAPI).Status.StatusList(req, nil)
 }
 
-/* TODO
-func TestInvalidHttpsCertificate(t *testing.T) {}
-*/
+func TestHTTPProxy(t *testing.T) {
+
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.Host
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"netbox-version": "3.1.11"}`))
+	}))
+	defer proxy.Close()
+
+	config := Config{
+		APIToken:  "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL: "http://netbox.example.com",
+		HTTPProxy: proxy.URL,
+	}
+
+	client, err := config.Client()
+	assert.NoError(t, err)
+
+	req := status.NewStatusListParams()
+	_, err = client.(*netboxClient.NetBoxAPI).Status.StatusList(req, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "netbox.example.com", proxiedHost)
+}
+
+func TestNoProxy(t *testing.T) {
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to proxy for %s", r.Host)
+	}))
+	defer proxy.Close()
+
+	config := Config{
+		APIToken:  "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL: "http://netbox.invalid",
+		HTTPProxy: proxy.URL,
+		NoProxy:   ".invalid",
+	}
+
+	client, err := config.Client()
+	assert.NoError(t, err)
+
+	req := status.NewStatusListParams()
+	_, err = client.(*netboxClient.NetBoxAPI).Status.StatusList(req, nil)
+	assert.Error(t, err)
+}
+
+func TestInvalidHttpsCertificate(t *testing.T) {
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	config := Config{
+		APIToken:  "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL: ts.URL,
+	}
+
+	client, err := config.Client()
+	assert.NoError(t, err)
+
+	req := status.NewStatusListParams()
+	_, err = client.(*netboxClient.NetBoxAPI).Status.StatusList(req, nil)
+	assert.ErrorContains(t, err, "certificate")
+}
+
+func TestCACertPEM(t *testing.T) {
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"netbox-version": "3.1.11"}`))
+	}))
+	defer ts.Close()
+
+	config := Config{
+		APIToken:  "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL: ts.URL,
+		CACertPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})),
+	}
+
+	client, err := config.Client()
+	assert.NoError(t, err)
+
+	req := status.NewStatusListParams()
+	_, err = client.(*netboxClient.NetBoxAPI).Status.StatusList(req, nil)
+	assert.NoError(t, err)
+}
+
+func TestCACertFile(t *testing.T) {
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"netbox-version": "3.1.11"}`))
+	}))
+	defer ts.Close()
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0600)
+	assert.NoError(t, err)
+
+	config := Config{
+		APIToken:   "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL:  ts.URL,
+		CACertFile: caCertFile,
+	}
+
+	client, err := config.Client()
+	assert.NoError(t, err)
+
+	req := status.NewStatusListParams()
+	_, err = client.(*netboxClient.NetBoxAPI).Status.StatusList(req, nil)
+	assert.NoError(t, err)
+}
+
+func TestInvalidCACert(t *testing.T) {
+
+	config := Config{
+		APIToken:  "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL: "https://localhost:8080",
+		CACertPEM: "not a certificate",
+	}
+
+	_, err := config.Client()
+	assert.Error(t, err)
+
+	config = Config{
+		APIToken:   "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL:  "https://localhost:8080",
+		CACertFile: filepath.Join(t.TempDir(), "missing.pem"),
+	}
+
+	_, err = config.Client()
+	assert.Error(t, err)
+}
+
+func TestClientCertificate(t *testing.T) {
+
+	clientCert, clientKey := generateTestClientCertificate(t)
+
+	clientCAs := x509.NewCertPool()
+	assert.True(t, clientCAs.AppendCertsFromPEM(clientCert))
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Len(t, r.TLS.PeerCertificates, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"netbox-version": "3.1.11"}`))
+	}))
+	ts.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	dir := t.TempDir()
+	clientCertFile := filepath.Join(dir, "client.pem")
+	clientKeyFile := filepath.Join(dir, "client-key.pem")
+	assert.NoError(t, os.WriteFile(clientCertFile, clientCert, 0600))
+	assert.NoError(t, os.WriteFile(clientKeyFile, clientKey, 0600))
+
+	caCertPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}))
+
+	// Without a client certificate, the server rejects the handshake
+	config := Config{
+		APIToken:  "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL: ts.URL,
+		CACertPEM: caCertPEM,
+	}
+
+	client, err := config.Client()
+	assert.NoError(t, err)
+
+	req := status.NewStatusListParams()
+	_, err = client.(*netboxClient.NetBoxAPI).Status.StatusList(req, nil)
+	assert.Error(t, err)
+
+	config.ClientCertFile = clientCertFile
+	config.ClientKeyFile = clientKeyFile
+
+	client, err = config.Client()
+	assert.NoError(t, err)
+
+	_, err = client.(*netboxClient.NetBoxAPI).Status.StatusList(req, nil)
+	assert.NoError(t, err)
+}
+
+func TestClientCertificateWithoutKey(t *testing.T) {
+
+	config := Config{
+		APIToken:       "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL:      "https://localhost:8080",
+		ClientCertFile: "client.pem",
+	}
+
+	_, err := config.Client()
+	assert.Error(t, err)
+}
+
+// generateTestClientCertificate returns a PEM encoded self-signed client
+// certificate and its private key.
+func generateTestClientCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "terraform-provider-netbox"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
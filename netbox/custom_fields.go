@@ -1,11 +1,33 @@
 package netbox
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/extras"
+	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const customFieldsKey = "custom_fields"
 
+const customFieldsAllKey = "custom_fields_all"
+
+// Custom field types that the go-netbox models do not know about.
+const (
+	customFieldTypeJSON        = "json"
+	customFieldTypeObject      = "object"
+	customFieldTypeMultiobject = "multiobject"
+)
+
 var customFieldsSchema = &schema.Schema{
 	Type:     schema.TypeMap,
 	Optional: true,
@@ -14,12 +36,371 @@ var customFieldsSchema = &schema.Schema{
 		Type:    schema.TypeString,
 		Default: nil,
 	},
+	Description: "Values of custom fields, keyed by the name of the custom field. Values are encoded according to the type of the custom field: integers and booleans as their string representation, multiselect fields as a list encoded with `jsonencode` or as a comma-separated string, JSON fields as an encoded string and object fields by the ID of the referenced object. An empty string or removing a field clears it.",
+}
+
+var customFieldsAllSchema = &schema.Schema{
+	Type: schema.TypeMap,
+	Elem: &schema.Schema{
+		Type: schema.TypeString,
+	},
+	Computed:    true,
+	Description: "Values of all custom fields of the object that are set in Netbox, including the ones that are not managed by Terraform. Values are encoded like in `custom_fields`.",
+}
+
+// customFieldCache holds the types of all custom fields defined in Netbox, so
+// that custom field values can be converted without one API call per field.
+// The cache is loaded lazily on first use and is safe for concurrent use.
+type customFieldCache struct {
+	api *client.NetBoxAPI
+
+	mu     sync.Mutex
+	loaded bool
+	types  map[string]string
+}
+
+func newCustomFieldCache(api *client.NetBoxAPI) *customFieldCache {
+	return &customFieldCache{
+		api: api,
+	}
+}
+
+// invalidate drops all cached custom fields. The next lookup reloads them from
+// Netbox.
+func (c *customFieldCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loaded = false
+	c.types = nil
+}
+
+// load fetches all custom fields from Netbox. The caller must hold the lock.
+func (c *customFieldCache) load() error {
+	customFields, err := listAll(func(limit int64, offset int64) (listPage[*models.CustomField], error) {
+		params := extras.NewExtrasCustomFieldsListParams()
+		params.Limit = &limit
+		params.Offset = &offset
+
+		res, err := c.api.Extras.ExtrasCustomFieldsList(params, nil)
+		if err != nil {
+			return listPage[*models.CustomField]{}, err
+		}
+		payload := res.GetPayload()
+		return listPage[*models.CustomField]{
+			results: payload.Results,
+			count:   payload.Count,
+			hasNext: payload.Next != nil,
+		}, nil
+	}, 0)
+	if err != nil {
+		return err
+	}
+
+	types := make(map[string]string)
+	for _, customField := range customFields {
+		if customField.Name == nil || customField.Type == nil || customField.Type.Value == nil {
+			continue
+		}
+		types[*customField.Name] = *customField.Type.Value
+	}
+
+	c.types = types
+	c.loaded = true
+	return nil
+}
+
+// lookupType returns the type of the custom field with the given name, or an
+// empty string if Netbox does not know the field. A miss reloads the cache
+// once, in case the field was created after the cache was loaded.
+func (c *customFieldCache) lookupType(name string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reloaded := false
+	if !c.loaded {
+		if err := c.load(); err != nil {
+			return "", err
+		}
+		reloaded = true
+	}
+
+	for {
+		if customFieldType, ok := c.types[name]; ok {
+			return customFieldType, nil
+		}
+		if reloaded {
+			return "", nil
+		}
+		if err := c.load(); err != nil {
+			return "", err
+		}
+		reloaded = true
+	}
+}
+
+// getCustomFieldsFromResourceData converts the custom_fields attribute of a
+// resource to the typed values Netbox expects. Fields that were removed from
+// the attribute are sent as null so that Netbox clears them. It returns nil if
+// there is nothing to send, so that the result can be assigned to the
+// CustomFields of a model directly.
+func getCustomFieldsFromResourceData(api *providerState, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	oldValue, newValue := d.GetChange(customFieldsKey)
+	oldFields, _ := oldValue.(map[string]interface{})
+	newFields, _ := newValue.(map[string]interface{})
+
+	customFields := make(map[string]interface{})
+	for _, name := range getCustomFieldNames(newFields) {
+		value := newFields[name]
+		path := cty.GetAttrPath(customFieldsKey).IndexString(name)
+
+		customFieldType, err := api.customFieldCache.lookupType(name)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Error retrieving custom field %s from netbox", name),
+				Detail:        fmt.Sprintf("API Error trying to retrieve custom field %s from netbox: %v", name, err),
+				AttributePath: path,
+			})
+			continue
+		}
+		if customFieldType == "" {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Custom field %s not found in netbox", name),
+				Detail:        fmt.Sprintf("Could not find a custom field named %s in netbox. Create the custom field first, e.g. with the netbox_custom_field resource.", name),
+				AttributePath: path,
+			})
+			continue
+		}
+
+		encoded, err := encodeCustomFieldValue(customFieldType, value.(string))
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Invalid value for custom field %s", name),
+				Detail:        fmt.Sprintf("The value of the %s custom field %s is invalid: %v", customFieldType, name, err),
+				AttributePath: path,
+			})
+			continue
+		}
+		customFields[name] = encoded
+	}
+
+	for name := range oldFields {
+		if _, ok := newFields[name]; !ok {
+			customFields[name] = nil
+		}
+	}
+
+	if len(customFields) == 0 {
+		return nil, diags
+	}
+	return customFields, diags
+}
+
+// setCustomFieldsFromAPI sets the custom_fields and custom_fields_all
+// attributes of a resource from the custom fields returned by Netbox. Fields
+// without a value are left out. If ignore_unmanaged_custom_fields is set,
+// custom_fields only contains the fields that are configured on the resource,
+// so that fields filled in by other tools do not show up as a diff. Values
+// that are equal to the configured value, e.g. a JSON document with different
+// whitespace, keep their configured representation.
+func setCustomFieldsFromAPI(api *providerState, d *schema.ResourceData, apiCustomFields interface{}) error {
+	configured, _ := d.Get(customFieldsKey).(map[string]interface{})
+	apiFields, _ := apiCustomFields.(map[string]interface{})
+
+	customFields := make(map[string]interface{})
+	customFieldsAll := make(map[string]interface{})
+	for name, value := range apiFields {
+		configuredValue, isConfigured := configured[name].(string)
+		if value == nil {
+			if isConfigured && configuredValue == "" {
+				customFields[name] = ""
+			}
+			continue
+		}
+
+		// Without the definition of the field, e.g. if the API token may not
+		// view custom fields, the value is converted based on its JSON type.
+		customFieldType, _ := api.customFieldCache.lookupType(name)
+
+		decoded, err := decodeCustomFieldValue(customFieldType, value)
+		if err != nil {
+			return fmt.Errorf("custom field %s: %w", name, err)
+		}
+		customFieldsAll[name] = decoded
+
+		if isConfigured && customFieldValueEqual(customFieldType, configuredValue, value) {
+			customFields[name] = configuredValue
+		} else if isConfigured || !api.ignoreUnmanagedCustomFields {
+			customFields[name] = decoded
+		}
+	}
+
+	if err := d.Set(customFieldsKey, customFields); err != nil {
+		return err
+	}
+	return d.Set(customFieldsAllKey, customFieldsAll)
+}
+
+// customizeDiffCustomFieldsAll marks custom_fields_all as changing whenever
+// custom_fields changes, as its new value is only known after the update.
+func customizeDiffCustomFieldsAll(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.HasChange(customFieldsKey) {
+		return d.SetNewComputed(customFieldsAllKey)
+	}
+	return nil
+}
+
+// encodeCustomFieldValue converts the string representation of a custom field
+// value to the value Netbox expects for the given type. An empty string
+// clears the field.
+func encodeCustomFieldValue(customFieldType string, value string) (interface{}, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	switch customFieldType {
+	case models.CustomFieldTypeValueInteger, customFieldTypeObject:
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	case models.CustomFieldTypeValueBoolean:
+		return strconv.ParseBool(strings.TrimSpace(value))
+	case models.CustomFieldTypeValueMultiselect:
+		return parseCustomFieldList(value)
+	case customFieldTypeMultiobject:
+		items, err := parseCustomFieldList(value)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int64, 0, len(items))
+		for _, item := range items {
+			id, err := strconv.ParseInt(item, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	case customFieldTypeJSON:
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			return nil, fmt.Errorf("not a valid JSON document: %w", err)
+		}
+		return decoded, nil
+	default:
+		return value, nil
+	}
+}
+
+// decodeCustomFieldValue converts a custom field value returned by Netbox to
+// its string representation. Lists are encoded as JSON.
+func decodeCustomFieldValue(customFieldType string, value interface{}) (string, error) {
+	switch customFieldType {
+	case customFieldTypeObject, customFieldTypeMultiobject:
+		value = getCustomFieldObjectIDs(value)
+	case customFieldTypeJSON:
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		encoded, err := json.Marshal(v)
+		return string(encoded), err
+	}
+}
+
+// customFieldValueEqual returns true if the configured string representation
+// of a custom field value denotes the value returned by Netbox.
+func customFieldValueEqual(customFieldType string, configured string, value interface{}) bool {
+	encoded, err := encodeCustomFieldValue(customFieldType, configured)
+	if err != nil {
+		return false
+	}
+	if customFieldType == customFieldTypeObject || customFieldType == customFieldTypeMultiobject {
+		value = getCustomFieldObjectIDs(value)
+	}
+
+	left, err := json.Marshal(encoded)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
+
+// parseCustomFieldList parses a list given either as a JSON array of strings
+// or as a comma-separated string.
+func parseCustomFieldList(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") {
+		var items []interface{}
+		if err := json.Unmarshal([]byte(value), &items); err != nil {
+			return nil, fmt.Errorf("not a valid JSON list: %w", err)
+		}
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			switch v := item.(type) {
+			case string:
+				list = append(list, v)
+			case float64:
+				list = append(list, strconv.FormatFloat(v, 'f', -1, 64))
+			default:
+				return nil, fmt.Errorf("unexpected list item %v", item)
+			}
+		}
+		return list, nil
+	}
+
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
+
+// getCustomFieldObjectIDs replaces the nested objects Netbox returns for
+// object fields by their IDs.
+func getCustomFieldObjectIDs(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if id, ok := v["id"]; ok {
+			return id
+		}
+		return v
+	case []interface{}:
+		ids := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			ids = append(ids, getCustomFieldObjectIDs(item))
+		}
+		return ids
+	default:
+		return v
+	}
 }
 
-func getCustomFields(cf interface{}) map[string]interface{} {
-	cfm, ok := cf.(map[string]interface{})
-	if !ok || len(cfm) == 0 {
-		return nil
+// getCustomFieldNames returns the sorted names of the given custom fields.
+func getCustomFieldNames(customFields map[string]interface{}) []string {
+	names := make([]string, 0, len(customFields))
+	for name := range customFields {
+		names = append(names, name)
 	}
-	return cfm
+	sort.Strings(names)
+	return names
 }
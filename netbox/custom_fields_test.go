@@ -0,0 +1,304 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// testCustomFieldProviderState returns a provider state whose Netbox knows
+// custom fields of the given types.
+func testCustomFieldProviderState(t *testing.T, types map[string]string) *providerState {
+	customFields := []map[string]interface{}{}
+	for name, customFieldType := range types {
+		customFields = append(customFields, map[string]interface{}{
+			"id":   len(customFields) + 1,
+			"name": name,
+			"type": map[string]interface{}{"value": customFieldType, "label": customFieldType},
+		})
+	}
+
+	return newTestProviderState(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/extras/custom-fields/", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count":   len(customFields),
+			"next":    nil,
+			"results": customFields,
+		})
+	})
+}
+
+// testDecodeJSON decodes a JSON document the way the Netbox client does.
+func testDecodeJSON(t *testing.T, document string) interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(document), &value); err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestEncodeCustomFieldValue(t *testing.T) {
+
+	for _, tc := range []struct {
+		customFieldType string
+		value           string
+		expected        interface{}
+	}{
+		{"text", "foo", "foo"},
+		{"text", "", nil},
+		{"integer", "42", int64(42)},
+		{"integer", "", nil},
+		{"boolean", "true", true},
+		{"boolean", "false", false},
+		{"multiselect", `["a","b"]`, []string{"a", "b"}},
+		{"multiselect", "a, b", []string{"a", "b"}},
+		{"json", `{"foo": [1, 2]}`, map[string]interface{}{"foo": []interface{}{float64(1), float64(2)}}},
+		{"object", "3", int64(3)},
+		{"multiobject", "[3,4]", []int64{3, 4}},
+		{"multiobject", "3,4", []int64{3, 4}},
+	} {
+		encoded, err := encodeCustomFieldValue(tc.customFieldType, tc.value)
+		assert.NoError(t, err, tc.customFieldType)
+		assert.Equal(t, tc.expected, encoded, tc.customFieldType)
+	}
+
+	for _, tc := range []struct {
+		customFieldType string
+		value           string
+	}{
+		{"integer", "foo"},
+		{"boolean", "yes please"},
+		{"multiselect", `["a",`},
+		{"json", "{"},
+		{"object", "foo"},
+		{"multiobject", "3,foo"},
+	} {
+		_, err := encodeCustomFieldValue(tc.customFieldType, tc.value)
+		assert.Error(t, err, tc.customFieldType)
+	}
+}
+
+func TestDecodeCustomFieldValue(t *testing.T) {
+
+	for _, tc := range []struct {
+		customFieldType string
+		value           string
+		expected        string
+	}{
+		{"text", `"foo"`, "foo"},
+		{"integer", "42", "42"},
+		{"integer", "1000000", "1000000"},
+		{"boolean", "true", "true"},
+		{"multiselect", `["a","b"]`, `["a","b"]`},
+		{"json", `{"foo": [1, 2]}`, `{"foo":[1,2]}`},
+		{"json", `"foo"`, `"foo"`},
+		{"object", `{"id": 3, "url": "http://netbox/api/dcim/sites/3/", "display": "site"}`, "3"},
+		{"multiobject", `[{"id": 3}, {"id": 4}]`, "[3,4]"},
+	} {
+		decoded, err := decodeCustomFieldValue(tc.customFieldType, testDecodeJSON(t, tc.value))
+		assert.NoError(t, err, tc.customFieldType)
+		assert.Equal(t, tc.expected, decoded, tc.customFieldType)
+	}
+}
+
+func TestCustomFieldValueEqual(t *testing.T) {
+
+	for _, tc := range []struct {
+		customFieldType string
+		configured      string
+		value           string
+		expected        bool
+	}{
+		{"integer", "42", "42", true},
+		{"integer", "042", "42", true},
+		{"integer", "43", "42", false},
+		{"boolean", "True", "true", true},
+		{"multiselect", "a,b", `["a","b"]`, true},
+		{"multiselect", "b,a", `["a","b"]`, false},
+		{"json", "{\n  \"b\": 1,\n  \"a\": 2\n}", `{"a":2,"b":1}`, true},
+		{"object", "3", `{"id": 3, "display": "site"}`, true},
+		{"multiobject", "3,4", `[{"id": 3}, {"id": 4}]`, true},
+		{"integer", "foo", "42", false},
+	} {
+		assert.Equal(t, tc.expected, customFieldValueEqual(tc.customFieldType, tc.configured, testDecodeJSON(t, tc.value)), tc.configured)
+	}
+}
+
+func TestGetCustomFieldsFromResourceData(t *testing.T) {
+
+	api := testCustomFieldProviderState(t, map[string]string{
+		"count":   "integer",
+		"enabled": "boolean",
+		"colors":  "multiselect",
+	})
+	d := schema.TestResourceDataRaw(t, resourceNetboxSite().Schema, map[string]interface{}{
+		"name": "site",
+		"custom_fields": map[string]interface{}{
+			"count":   "3",
+			"enabled": "true",
+			"colors":  `["red","blue"]`,
+		},
+	})
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, map[string]interface{}{
+		"count":   int64(3),
+		"enabled": true,
+		"colors":  []string{"red", "blue"},
+	}, customFields)
+}
+
+func TestGetCustomFieldsFromResourceDataErrors(t *testing.T) {
+
+	api := testCustomFieldProviderState(t, map[string]string{
+		"count": "integer",
+	})
+	d := schema.TestResourceDataRaw(t, resourceNetboxSite().Schema, map[string]interface{}{
+		"name": "site",
+		"custom_fields": map[string]interface{}{
+			"count":   "three",
+			"missing": "foo",
+		},
+	})
+
+	_, diags := getCustomFieldsFromResourceData(api, d)
+	assert.Len(t, diags, 2)
+	assert.Equal(t, "Invalid value for custom field count", diags[0].Summary)
+	assert.Equal(t, cty.GetAttrPath("custom_fields").IndexString("count"), diags[0].AttributePath)
+	assert.Equal(t, "Custom field missing not found in netbox", diags[1].Summary)
+}
+
+func TestSetCustomFieldsFromAPI(t *testing.T) {
+
+	api := testCustomFieldProviderState(t, map[string]string{
+		"count":   "integer",
+		"enabled": "boolean",
+		"colors":  "multiselect",
+		"config":  "json",
+		"unset":   "text",
+		"cleared": "text",
+	})
+	d := schema.TestResourceDataRaw(t, resourceNetboxSite().Schema, map[string]interface{}{
+		"name": "site",
+		"custom_fields": map[string]interface{}{
+			"colors":  "red, blue",
+			"config":  `{ "foo": "bar" }`,
+			"cleared": "",
+		},
+	})
+
+	err := setCustomFieldsFromAPI(api, d, testDecodeJSON(t, `{
+		"count": 3,
+		"enabled": false,
+		"colors": ["red", "blue"],
+		"config": {"foo": "bar"},
+		"unset": null,
+		"cleared": null
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"count":   "3",
+		"enabled": "false",
+		"colors":  "red, blue",
+		"config":  `{ "foo": "bar" }`,
+		"cleared": "",
+	}, d.Get(customFieldsKey))
+	assert.Equal(t, map[string]interface{}{
+		"count":   "3",
+		"enabled": "false",
+		"colors":  `["red","blue"]`,
+		"config":  `{"foo":"bar"}`,
+	}, d.Get(customFieldsAllKey))
+}
+
+func TestSetCustomFieldsFromAPIIgnoreUnmanaged(t *testing.T) {
+
+	api := testCustomFieldProviderState(t, map[string]string{
+		"count": "integer",
+		"owner": "text",
+		"notes": "text",
+	})
+	api.ignoreUnmanagedCustomFields = true
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxSite().Schema, map[string]interface{}{
+		"name": "site",
+		"custom_fields": map[string]interface{}{
+			"count": "3",
+			"notes": "",
+		},
+	})
+
+	err := setCustomFieldsFromAPI(api, d, testDecodeJSON(t, `{
+		"count": 4,
+		"owner": "sync job",
+		"notes": null
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"count": "4",
+		"notes": "",
+	}, d.Get(customFieldsKey))
+	assert.Equal(t, map[string]interface{}{
+		"count": "4",
+		"owner": "sync job",
+	}, d.Get(customFieldsAllKey))
+}
+
+func TestGetCustomFieldsFromResourceDataClearsRemovedFields(t *testing.T) {
+
+	api := testCustomFieldProviderState(t, map[string]string{
+		"count": "integer",
+		"owner": "text",
+	})
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"custom_fields.%":     "2",
+			"custom_fields.count": "3",
+			"custom_fields.owner": "me",
+		},
+	}
+	diff := &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"custom_fields.%":     {Old: "2", New: "1"},
+			"custom_fields.count": {Old: "3", New: "4"},
+			"custom_fields.owner": {Old: "me", NewRemoved: true},
+		},
+	}
+	d, err := schema.InternalMap(resourceNetboxSite().Schema).Data(state, diff)
+	assert.NoError(t, err)
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, map[string]interface{}{
+		"count": int64(4),
+		"owner": nil,
+	}, customFields)
+}
+
+func TestResourcesWithCustomFields(t *testing.T) {
+
+	resources := Provider().ResourcesMap
+	for _, name := range []string{
+		"netbox_circuit",
+		"netbox_cluster",
+		"netbox_device",
+		"netbox_ip_address",
+		"netbox_prefix",
+		"netbox_site",
+		"netbox_tenant",
+		"netbox_virtual_machine",
+		"netbox_vlan",
+		"netbox_vrf",
+	} {
+		assert.Contains(t, resources[name].Schema, customFieldsKey, name)
+	}
+}
@@ -2,10 +2,8 @@ package netbox
 
 import (
 	"errors"
-	"fmt"
 	"regexp"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/virtualization"
 	"github.com/fbreckle/go-netbox/netbox/models"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -17,27 +15,18 @@ func dataSourceNetboxInterfaces() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceNetboxInterfaceRead,
 		Schema: map[string]*schema.Schema{
-			"filter": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"value": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
-			},
+			"filter": listFilterSchema(),
 			"name_regex": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validation.StringIsValidRegExp,
 			},
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum number of results returned. By default, all matching objects are returned.",
+			},
 			"interfaces": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -131,52 +120,51 @@ func dataSourceNetboxInterfaces() *schema.Resource {
 }
 
 func dataSourceNetboxInterfaceRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+	api := m.(*providerState)
 
 	params := virtualization.NewVirtualizationInterfacesListParams()
 
-	if filter, ok := d.GetOk("filter"); ok {
-		var filterParams = filter.(*schema.Set)
-		for _, f := range filterParams.List() {
-			k := f.(map[string]interface{})["name"]
-			v := f.(map[string]interface{})["value"]
-			vString := v.(string)
-			switch k {
-			case "cluster_id":
-				params.ClusterID = &vString
-			case "mac_address":
-				params.MacAddress = &vString
-			case "name":
-				params.Name = &vString
-			case "tag":
-				params.Tag = &vString
-			case "vm_id":
-				params.VirtualMachineID = &vString
-			default:
-				return fmt.Errorf("'%s' is not a supported filter parameter", k)
-			}
-		}
+	filter, err := getListFilter(d, map[string]string{
+		"vm_id": "virtual_machine_id",
+	})
+	if err != nil {
+		return err
 	}
 
-	res, err := api.Virtualization.VirtualizationInterfacesList(params, nil)
+	vmInterfaces, err := listAll(func(limit int64, offset int64) (listPage[*models.VMInterface], error) {
+		pageParams := *params
+		pageParams.Limit = &limit
+		pageParams.Offset = &offset
+
+		res, err := api.Virtualization.VirtualizationInterfacesList(&pageParams, nil, withQueryParams(filter))
+		if err != nil {
+			return listPage[*models.VMInterface]{}, err
+		}
+		payload := res.GetPayload()
+		return listPage[*models.VMInterface]{
+			results: payload.Results,
+			count:   payload.Count,
+			hasNext: payload.Next != nil,
+		}, nil
+	}, int64(d.Get("limit").(int)))
 	if err != nil {
 		return err
 	}
 
-	if *res.GetPayload().Count == int64(0) {
+	if len(vmInterfaces) == 0 {
 		return errors.New("no result")
 	}
 
 	var filteredInterfaces []*models.VMInterface
 	if nameRegex, ok := d.GetOk("name_regex"); ok {
 		r := regexp.MustCompile(nameRegex.(string))
-		for _, vmInterface := range res.GetPayload().Results {
+		for _, vmInterface := range vmInterfaces {
 			if r.MatchString(*vmInterface.Name) {
 				filteredInterfaces = append(filteredInterfaces, vmInterface)
 			}
 		}
 	} else {
-		filteredInterfaces = res.GetPayload().Results
+		filteredInterfaces = vmInterfaces
 	}
 
 	var s []map[string]interface{}
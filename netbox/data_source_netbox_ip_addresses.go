@@ -2,36 +2,26 @@ package netbox
 
 import (
 	"errors"
-	"fmt"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/ipam"
 	"github.com/fbreckle/go-netbox/netbox/models"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceNetboxIpAddresses() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceNetboxIpAddressesRead,
 		Schema: map[string]*schema.Schema{
-			"filter": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"value": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
-			},
+			"filter": listFilterSchema(),
 
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum number of results returned. By default, all matching objects are returned.",
+			},
 			"ip_addresses": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -106,43 +96,43 @@ func dataSourceNetboxIpAddresses() *schema.Resource {
 }
 
 func dataSourceNetboxIpAddressesRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+	api := m.(*providerState)
 
 	params := ipam.NewIpamIPAddressesListParams()
 
-	if filter, ok := d.GetOk("filter"); ok {
-		var filterParams = filter.(*schema.Set)
-		for _, f := range filterParams.List() {
-			k := f.(map[string]interface{})["name"]
-			v := f.(map[string]interface{})["value"]
-			vString := v.(string)
-			switch k {
-			case "dns_name":
-				params.DNSName = &vString
-			case "interface_id":
-				params.InterfaceID = &vString
-			case "device_id":
-				params.DeviceID = &vString
-			case "ip_address":
-				params.Address = &vString
-			case "vm_interface_id":
-				params.VminterfaceID = &vString
-			default:
-				return fmt.Errorf("'%s' is not a supported filter parameter", k)
-			}
-		}
+	filter, err := getListFilter(d, map[string]string{
+		"ip_address":      "address",
+		"vm_interface_id": "vminterface_id",
+	})
+	if err != nil {
+		return err
 	}
 
-	res, err := api.Ipam.IpamIPAddressesList(params, nil)
+	ipAddresses, err := listAll(func(limit int64, offset int64) (listPage[*models.IPAddress], error) {
+		pageParams := *params
+		pageParams.Limit = &limit
+		pageParams.Offset = &offset
+
+		res, err := api.Ipam.IpamIPAddressesList(&pageParams, nil, withQueryParams(filter))
+		if err != nil {
+			return listPage[*models.IPAddress]{}, err
+		}
+		payload := res.GetPayload()
+		return listPage[*models.IPAddress]{
+			results: payload.Results,
+			count:   payload.Count,
+			hasNext: payload.Next != nil,
+		}, nil
+	}, int64(d.Get("limit").(int)))
 	if err != nil {
 		return err
 	}
 
-	if *res.GetPayload().Count == int64(0) {
+	if len(ipAddresses) == 0 {
 		return errors.New("no result")
 	}
 
-	filteredIpAddresses := res.GetPayload().Results
+	filteredIpAddresses := ipAddresses
 
 	var s []map[string]interface{}
 	for _, v := range filteredIpAddresses {
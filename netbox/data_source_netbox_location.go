@@ -0,0 +1,115 @@
+package netbox
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceNetboxLocation() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNetboxLocationRead,
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: []string{"name", "slug"},
+			},
+			"slug": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				AtLeastOneOf: []string{"name", "slug"},
+			},
+			"site_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"parent_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"tenant_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceNetboxLocationRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*providerState)
+
+	params := dcim.NewDcimLocationsListParams()
+	limit := int64(2) // Limit of 2 is enough
+	params.Limit = &limit
+
+	if name, ok := d.GetOk("name"); ok {
+		name := name.(string)
+		params.Name = &name
+	}
+
+	if slug, ok := d.GetOk("slug"); ok {
+		slug := slug.(string)
+		params.Slug = &slug
+	}
+
+	if siteID, ok := d.GetOk("site_id"); ok {
+		siteID := strconv.Itoa(siteID.(int))
+		params.SiteID = &siteID
+	}
+
+	var fields struct {
+		Results []locationFields `json:"results"`
+	}
+	res, err := api.Dcim.DcimLocationsList(params, nil, withResponseFields(&fields))
+	if err != nil {
+		return err
+	}
+
+	if *res.GetPayload().Count > int64(1) {
+		return errors.New("More than one result. Specify a more narrow filter")
+	}
+	if *res.GetPayload().Count == int64(0) {
+		return errors.New("No result")
+	}
+	result := res.GetPayload().Results[0]
+	d.SetId(strconv.FormatInt(result.ID, 10))
+	d.Set("name", result.Name)
+	d.Set("slug", result.Slug)
+	d.Set("description", result.Description)
+
+	if result.Site != nil {
+		d.Set("site_id", result.Site.ID)
+	}
+
+	if result.Parent != nil {
+		d.Set("parent_id", result.Parent.ID)
+	} else {
+		d.Set("parent_id", nil)
+	}
+
+	if len(fields.Results) > 0 {
+		if fields.Results[0].Status != nil {
+			d.Set("status", fields.Results[0].Status.Value)
+		}
+		if fields.Results[0].Tenant != nil {
+			d.Set("tenant_id", fields.Results[0].Tenant.ID)
+		} else {
+			d.Set("tenant_id", nil)
+		}
+	}
+	return nil
+}
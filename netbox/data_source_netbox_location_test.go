@@ -0,0 +1,47 @@
+package netbox
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccNetboxLocationDataSource_basic(t *testing.T) {
+
+	testSlug := "location_ds_basic"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNetboxLocationFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_location" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+  tenant_id = netbox_tenant.test.id
+  status = "planned"
+}
+
+data "netbox_location" "by_name" {
+  depends_on = [netbox_location.test]
+  name = "%[1]s"
+}
+
+data "netbox_location" "by_slug" {
+  depends_on = [netbox_location.test]
+  slug = netbox_location.test.slug
+  site_id = netbox_site.test.id
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.netbox_location.by_name", "id", "netbox_location.test", "id"),
+					resource.TestCheckResourceAttrPair("data.netbox_location.by_name", "site_id", "netbox_site.test", "id"),
+					resource.TestCheckResourceAttrPair("data.netbox_location.by_name", "tenant_id", "netbox_tenant.test", "id"),
+					resource.TestCheckResourceAttr("data.netbox_location.by_name", "status", "planned"),
+					resource.TestCheckResourceAttrPair("data.netbox_location.by_slug", "id", "netbox_location.test", "id"),
+				),
+			},
+		},
+	})
+}
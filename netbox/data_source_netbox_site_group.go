@@ -0,0 +1,110 @@
+package netbox
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceNetboxSiteGroup() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNetboxSiteGroupRead,
+		Schema: map[string]*schema.Schema{
+			"filter": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"slug": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringLenBetween(0, 100),
+						},
+					},
+				},
+			},
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"slug": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"parent_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceNetboxSiteGroupRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*providerState)
+
+	params := dcim.NewDcimSiteGroupsListParams()
+
+	if filter, ok := d.GetOk("filter"); ok {
+		var filterParams = filter.(*schema.Set)
+		for _, f := range filterParams.List() {
+			id := f.(map[string]interface{})["id"]
+			if id != nil {
+				vId := id.(int)
+				if vId != 0 {
+					vIdString := strconv.Itoa(vId)
+					params.ID = &vIdString
+				}
+			}
+			name := f.(map[string]interface{})["name"]
+			if name != nil {
+				vName := name.(string)
+				params.Name = &vName
+			}
+			slug := f.(map[string]interface{})["slug"]
+			if slug != nil {
+				vSlug := slug.(string)
+				params.Slug = &vSlug
+			}
+		}
+	}
+
+	res, err := api.Dcim.DcimSiteGroupsList(params, nil)
+	if err != nil {
+		return err
+	}
+
+	if *res.GetPayload().Count > int64(1) {
+		return errors.New("More than one result. Specify a more narrow filter")
+	}
+	if *res.GetPayload().Count == int64(0) {
+		return errors.New("No result")
+	}
+	result := res.GetPayload().Results[0]
+	d.SetId(strconv.FormatInt(result.ID, 10))
+	d.Set("name", result.Name)
+	d.Set("slug", result.Slug)
+	d.Set("description", result.Description)
+	if result.Parent != nil {
+		d.Set("parent_id", result.Parent.ID)
+	}
+	return nil
+}
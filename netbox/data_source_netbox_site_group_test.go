@@ -0,0 +1,41 @@
+package netbox
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccNetboxSiteGroupDataSource_basic(t *testing.T) {
+
+	testSlug := "site_group_ds_basic"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+
+resource "netbox_site_group" "parent" {
+  name = "%[1]s-parent"
+}
+resource "netbox_site_group" "test" {
+  name = "%[1]s"
+  parent_id = netbox_site_group.parent.id
+}
+data "netbox_site_group" "test" {
+  depends_on = [netbox_site_group.test]
+  filter {
+	name = "%[1]s"
+  }
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.netbox_site_group.test", "id", "netbox_site_group.test", "id"),
+					resource.TestCheckResourceAttrPair("data.netbox_site_group.test", "slug", "netbox_site_group.test", "slug"),
+					resource.TestCheckResourceAttrPair("data.netbox_site_group.test", "parent_id", "netbox_site_group.parent", "id"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,153 @@
+package netbox
+
+import (
+	"errors"
+
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceNetboxSites() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNetboxSitesRead,
+		Schema: map[string]*schema.Schema{
+			"filter": listFilterSchema(),
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum number of results returned. By default, all matching objects are returned.",
+			},
+			"sites": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"slug": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"facility": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"timezone": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"group_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"region_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"tenant_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"tag_ids": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeInt,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceNetboxSitesRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*providerState)
+
+	params := dcim.NewDcimSitesListParams()
+
+	filter, err := getListFilter(d, nil)
+	if err != nil {
+		return err
+	}
+
+	sites, err := listAll(func(limit int64, offset int64) (listPage[*models.Site], error) {
+		pageParams := *params
+		pageParams.Limit = &limit
+		pageParams.Offset = &offset
+
+		res, err := api.Dcim.DcimSitesList(&pageParams, nil, withQueryParams(filter))
+		if err != nil {
+			return listPage[*models.Site]{}, err
+		}
+		payload := res.GetPayload()
+		return listPage[*models.Site]{
+			results: payload.Results,
+			count:   payload.Count,
+			hasNext: payload.Next != nil,
+		}, nil
+	}, int64(d.Get("limit").(int)))
+	if err != nil {
+		return err
+	}
+
+	if len(sites) == 0 {
+		return errors.New("no result")
+	}
+
+	var s []map[string]interface{}
+	for _, v := range sites {
+		var mapping = make(map[string]interface{})
+
+		mapping["id"] = v.ID
+		mapping["name"] = v.Name
+		mapping["slug"] = v.Slug
+		mapping["description"] = v.Description
+		mapping["facility"] = v.Facility
+		mapping["timezone"] = v.TimeZone
+
+		if v.Status != nil {
+			mapping["status"] = v.Status.Value
+		}
+		if v.Group != nil {
+			mapping["group_id"] = v.Group.ID
+		}
+		if v.Region != nil {
+			mapping["region_id"] = v.Region.ID
+		}
+		if v.Tenant != nil {
+			mapping["tenant_id"] = v.Tenant.ID
+		}
+		if v.Tags != nil {
+			var tags []int64
+			for _, t := range v.Tags {
+				tags = append(tags, t.ID)
+			}
+			mapping["tag_ids"] = tags
+		}
+
+		s = append(s, mapping)
+	}
+
+	d.SetId(resource.UniqueId())
+	return d.Set("sites", s)
+}
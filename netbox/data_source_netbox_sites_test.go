@@ -0,0 +1,57 @@
+package netbox
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccNetboxSitesDataSource_filter(t *testing.T) {
+
+	testSlug := "sites_ds_filter"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "netbox_site_group" "test" {
+  name = "%[1]s"
+}
+resource "netbox_site" "test_0" {
+  name = "%[1]s_0"
+  status = "active"
+  group_id = netbox_site_group.test.id
+}
+resource "netbox_site" "test_1" {
+  name = "%[1]s_1"
+  status = "planned"
+  group_id = netbox_site_group.test.id
+}
+resource "netbox_site" "test_2" {
+  name = "%[1]s_2"
+  status = "active"
+}
+data "netbox_sites" "test" {
+  depends_on = [netbox_site.test_0, netbox_site.test_1, netbox_site.test_2]
+
+  filter {
+    name = "group_id"
+    value = netbox_site_group.test.id
+  }
+  filter {
+    name = "status"
+    value = "active"
+  }
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.netbox_sites.test", "sites.#", "1"),
+					resource.TestCheckResourceAttrPair("data.netbox_sites.test", "sites.0.id", "netbox_site.test_0", "id"),
+					resource.TestCheckResourceAttrPair("data.netbox_sites.test", "sites.0.group_id", "netbox_site_group.test", "id"),
+					resource.TestCheckResourceAttr("data.netbox_sites.test", "sites.0.status", "active"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,90 @@
+package netbox
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fbreckle/go-netbox/netbox/client/status"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceNetboxStatus() *schema.Resource {
+	return &schema.Resource{
+		Read:        dataSourceNetboxStatusRead,
+		Description: "Retrieves the version of the Netbox server and its components.",
+		Schema: map[string]*schema.Schema{
+			"netbox_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"django_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"python_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"plugins": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Installed plugins and their versions.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"rq_workers_running": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"supported": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the provider is tested against the Netbox version of the server.",
+			},
+		},
+	}
+}
+
+func dataSourceNetboxStatusRead(d *schema.ResourceData, m interface{}) error {
+	api := m.(*providerState)
+
+	params := status.NewStatusListParams()
+	res, err := api.Status.StatusList(params, nil)
+	if err != nil {
+		return err
+	}
+
+	netboxVersion, err := getNetboxVersionFromStatus(res.GetPayload())
+	if err != nil {
+		return err
+	}
+	payload := res.GetPayload().(map[string]interface{})
+
+	d.Set("netbox_version", netboxVersion)
+	d.Set("django_version", payload["django-version"])
+	d.Set("python_version", payload["python-version"])
+
+	plugins := make(map[string]interface{})
+	if p, ok := payload["plugins"].(map[string]interface{}); ok {
+		for name, version := range p {
+			plugins[name] = fmt.Sprintf("%v", version)
+		}
+	}
+	d.Set("plugins", plugins)
+
+	// The client decodes numbers as json.Number
+	if workers, ok := payload["rq-workers-running"].(json.Number); ok {
+		if n, err := workers.Int64(); err == nil {
+			d.Set("rq_workers_running", int(n))
+		}
+	}
+
+	version, err := semver.NewVersion(netboxVersion)
+	d.Set("supported", err == nil && isSupportedNetboxVersion(version))
+
+	d.SetId(resource.UniqueId())
+	return nil
+}
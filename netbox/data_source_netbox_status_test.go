@@ -0,0 +1,69 @@
+package netbox
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccNetboxStatusDataSource_basic(t *testing.T) {
+
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: `data "netbox_status" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.netbox_status.test", "netbox_version", regexp.MustCompile(`^\d+\.\d+\.\d+`)),
+					resource.TestCheckResourceAttr("data.netbox_status.test", "supported", "true"),
+					resource.TestCheckResourceAttrSet("data.netbox_status.test", "django_version"),
+					resource.TestCheckResourceAttrSet("data.netbox_status.test", "python_version"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceNetboxStatusRead(t *testing.T) {
+
+	api := newTestProviderState(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/status/", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"django-version": "3.2.12",
+			"installed-apps": {"django_filters": "21.1"},
+			"netbox-version": "3.1.9",
+			"plugins": {"netbox_bgp": "0.5.0"},
+			"python-version": "3.9.2",
+			"rq-workers-running": 2
+		}`))
+	})
+
+	d := dataSourceNetboxStatus().TestResourceData()
+	assert.NoError(t, dataSourceNetboxStatusRead(d, api))
+	assert.NotEmpty(t, d.Id())
+	assert.Equal(t, "3.1.9", d.Get("netbox_version"))
+	assert.Equal(t, "3.2.12", d.Get("django_version"))
+	assert.Equal(t, "3.9.2", d.Get("python_version"))
+	assert.Equal(t, map[string]interface{}{"netbox_bgp": "0.5.0"}, d.Get("plugins"))
+	assert.Equal(t, 2, d.Get("rq_workers_running"))
+	assert.Equal(t, true, d.Get("supported"))
+}
+
+func TestDataSourceNetboxStatusReadUnsupportedVersion(t *testing.T) {
+
+	api := newTestProviderState(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"netbox-version": "2.11.12"}`))
+	})
+
+	d := dataSourceNetboxStatus().TestResourceData()
+	assert.NoError(t, dataSourceNetboxStatusRead(d, api))
+	assert.Equal(t, "2.11.12", d.Get("netbox_version"))
+	assert.Equal(t, false, d.Get("supported"))
+}
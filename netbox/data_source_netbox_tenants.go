@@ -2,36 +2,26 @@ package netbox
 
 import (
 	"errors"
-	"fmt"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/tenancy"
 	"github.com/fbreckle/go-netbox/netbox/models"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceNetboxTenants() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceNetboxTenantsRead,
 		Schema: map[string]*schema.Schema{
-			"filter": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"value": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
-			},
+			"filter": listFilterSchema(),
 
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum number of results returned. By default, all matching objects are returned.",
+			},
 			"tenants": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -142,37 +132,40 @@ func dataSourceNetboxTenants() *schema.Resource {
 }
 
 func dataSourceNetboxTenantsRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+	api := m.(*providerState)
 
 	params := tenancy.NewTenancyTenantsListParams()
 
-	if filter, ok := d.GetOk("filter"); ok {
-		var filterParams = filter.(*schema.Set)
-		for _, f := range filterParams.List() {
-			k := f.(map[string]interface{})["name"]
-			v := f.(map[string]interface{})["value"]
-			vString := v.(string)
-			switch k {
-			case "name":
-				params.Name = &vString
-			case "slug":
-				params.Slug = &vString
-			default:
-				return fmt.Errorf("'%s' is not a supported filter parameter", k)
-			}
-		}
+	filter, err := getListFilter(d, nil)
+	if err != nil {
+		return err
 	}
 
-	res, err := api.Tenancy.TenancyTenantsList(params, nil)
+	tenants, err := listAll(func(limit int64, offset int64) (listPage[*models.Tenant], error) {
+		pageParams := *params
+		pageParams.Limit = &limit
+		pageParams.Offset = &offset
+
+		res, err := api.Tenancy.TenancyTenantsList(&pageParams, nil, withQueryParams(filter))
+		if err != nil {
+			return listPage[*models.Tenant]{}, err
+		}
+		payload := res.GetPayload()
+		return listPage[*models.Tenant]{
+			results: payload.Results,
+			count:   payload.Count,
+			hasNext: payload.Next != nil,
+		}, nil
+	}, int64(d.Get("limit").(int)))
 	if err != nil {
 		return err
 	}
 
-	if *res.GetPayload().Count == int64(0) {
+	if len(tenants) == 0 {
 		return errors.New("no result")
 	}
 
-	filteredTenants := res.GetPayload().Results
+	filteredTenants := tenants
 
 	var s []map[string]interface{}
 	for _, v := range filteredTenants {
@@ -3,10 +3,8 @@ package netbox
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"regexp"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/virtualization"
 	"github.com/fbreckle/go-netbox/netbox/models"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,30 +16,17 @@ func dataSourceNetboxVirtualMachine() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceNetboxVirtualMachineRead,
 		Schema: map[string]*schema.Schema{
-			"filter": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"value": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
-			},
+			"filter": listFilterSchema(),
 			"name_regex": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validation.StringIsValidRegExp,
 			},
 			"limit": {
-				Type:     schema.TypeInt,
-				Optional: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The maximum number of results returned. By default, all matching objects are returned.",
 			},
 			"vms": {
 				Type:     schema.TypeList,
@@ -135,64 +120,49 @@ func dataSourceNetboxVirtualMachine() *schema.Resource {
 }
 
 func dataSourceNetboxVirtualMachineRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+	api := m.(*providerState)
 
 	params := virtualization.NewVirtualizationVirtualMachinesListParams()
 
-	if filter, ok := d.GetOk("filter"); ok {
-		var filterParams = filter.(*schema.Set)
-		for _, f := range filterParams.List() {
-			k := f.(map[string]interface{})["name"]
-			v := f.(map[string]interface{})["value"]
-			switch k {
-			case "cluster_id":
-				var clusterString = v.(string)
-				params.ClusterID = &clusterString
-			case "cluster_group":
-				var clusterGroupString = v.(string)
-				params.ClusterGroup = &clusterGroupString
-			case "name":
-				var nameString = v.(string)
-				params.Name = &nameString
-			case "region":
-				var regionString = v.(string)
-				params.Region = &regionString
-			case "role":
-				var roleString = v.(string)
-				params.Role = &roleString
-			case "site":
-				var siteString = v.(string)
-				params.Site = &siteString
-			default:
-				return fmt.Errorf("'%s' is not a supported filter parameter", k)
-			}
-		}
+	filter, err := getListFilter(d, nil)
+	if err != nil {
+		return err
 	}
 
-	if limit, ok := d.GetOk("limit"); ok {
-		limitInt := int64(limit.(int))
-		params.Limit = &limitInt
-	}
+	vms, err := listAll(func(limit int64, offset int64) (listPage[*models.VirtualMachineWithConfigContext], error) {
+		pageParams := *params
+		pageParams.Limit = &limit
+		pageParams.Offset = &offset
 
-	res, err := api.Virtualization.VirtualizationVirtualMachinesList(params, nil)
+		res, err := api.Virtualization.VirtualizationVirtualMachinesList(&pageParams, nil, withQueryParams(filter))
+		if err != nil {
+			return listPage[*models.VirtualMachineWithConfigContext]{}, err
+		}
+		payload := res.GetPayload()
+		return listPage[*models.VirtualMachineWithConfigContext]{
+			results: payload.Results,
+			count:   payload.Count,
+			hasNext: payload.Next != nil,
+		}, nil
+	}, int64(d.Get("limit").(int)))
 	if err != nil {
 		return err
 	}
 
-	if *res.GetPayload().Count == int64(0) {
+	if len(vms) == 0 {
 		return errors.New("no result")
 	}
 
 	var filteredVms []*models.VirtualMachineWithConfigContext
 	if nameRegex, ok := d.GetOk("name_regex"); ok {
 		r := regexp.MustCompile(nameRegex.(string))
-		for _, vm := range res.GetPayload().Results {
+		for _, vm := range vms {
 			if r.MatchString(*vm.Name) {
 				filteredVms = append(filteredVms, vm)
 			}
 		}
 	} else {
-		filteredVms = res.GetPayload().Results
+		filteredVms = vms
 	}
 
 	var s []map[string]interface{}
@@ -0,0 +1,274 @@
+package netbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// netboxDefaultError is implemented by the *Default response types of the
+// generated go-netbox client, which carry the decoded JSON body of an
+// unsuccessful response.
+type netboxDefaultError interface {
+	error
+	Code() int
+	GetPayload() interface{}
+}
+
+// netboxNonFieldErrorKeys are the keys Netbox uses for validation errors that
+// do not belong to a single field.
+var netboxNonFieldErrorKeys = map[string]bool{
+	"__all__":          true,
+	"detail":           true,
+	"non_field_errors": true,
+}
+
+// netboxOperationRegexp matches the operation prefix of the error messages of
+// the generated go-netbox client, e.g. "[POST /dcim/sites/][400]".
+var netboxOperationRegexp = regexp.MustCompile(`^\[(\w+) ([^\]]+)\]\[\d+\]`)
+
+// netboxErrorHints replace the generic summary of well-known validation errors
+// of Netbox by a clearer one. They are matched against the error message.
+var netboxErrorHints = []struct {
+	pattern *regexp.Regexp
+	summary string
+}{
+	{regexp.MustCompile(`is already occupied or does not have sufficient space`), "Rack position is already occupied"},
+	{regexp.MustCompile(`Unable to delete object\. \d+ dependent objects were found`), "Object is still in use by dependent objects"},
+	{regexp.MustCompile(`already has a cable attached`), "Cable termination is already connected"},
+}
+
+// diagFromNetboxError translates an error returned by the Netbox API into
+// diagnostics. Validation errors are reported with one diagnostic per field,
+// attached to the matching attribute of the given resource schema. Permission
+// errors carry a hint about the missing object permission, and network errors
+// and requests refused in read-only mode are reported as such. All other
+// errors are passed through unchanged.
+func diagFromNetboxError(err error, resourceSchema map[string]*schema.Schema) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, errReadOnlyMode) {
+		return diag.Diagnostics{diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Netbox provider is in read-only mode",
+			Detail:   err.Error(),
+		}}
+	}
+
+	code, payload, ok := getNetboxErrorPayload(err)
+	if !ok {
+		if isNetworkError(err) {
+			return diag.Diagnostics{diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to communicate with Netbox",
+				Detail:   err.Error(),
+			}}
+		}
+		return diag.FromErr(err)
+	}
+
+	if code == http.StatusForbidden {
+		return diag.Diagnostics{netboxPermissionDiagnostic(err, payload)}
+	}
+
+	fields, ok := payload.(map[string]interface{})
+	if !ok || len(fields) == 0 || code < 400 || code >= 500 {
+		return diag.FromErr(err)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var diags diag.Diagnostics
+	for _, key := range keys {
+		message := getNetboxErrorMessage(fields[key])
+
+		if netboxNonFieldErrorKeys[key] {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  getNetboxErrorSummary(message, fmt.Sprintf("Netbox rejected the request (HTTP %d)", code)),
+				Detail:   message,
+			})
+			continue
+		}
+
+		d := diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Netbox rejected the value of %q", key),
+			Detail:   message,
+		}
+		if attribute, ok := getAttributeForNetboxField(key, resourceSchema); ok {
+			d.Summary = fmt.Sprintf("Netbox rejected the value of %q", attribute)
+			d.AttributePath = cty.GetAttrPath(attribute)
+		}
+		d.Summary = getNetboxErrorSummary(message, d.Summary)
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// isNotFoundError returns true if the error is a 404 response from Netbox. It
+// is safe to call with any error, e.g. a network error, which is never
+// considered a not found error.
+func isNotFoundError(err error) bool {
+	code, _, ok := getNetboxErrorPayload(err)
+	return ok && code == http.StatusNotFound
+}
+
+// isNetworkError returns true if the request did not receive a response from
+// Netbox at all, e.g. because of a DNS, TLS or timeout error.
+func isNetworkError(err error) bool {
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+// getNetboxErrorPayload extracts the status code and decoded body from an
+// error returned by the generated go-netbox client.
+func getNetboxErrorPayload(err error) (int, interface{}, bool) {
+	var defaultErr netboxDefaultError
+	if errors.As(err, &defaultErr) {
+		return defaultErr.Code(), defaultErr.GetPayload(), true
+	}
+
+	var apiErr *runtime.APIError
+	if errors.As(err, &apiErr) {
+		if response, ok := apiErr.Response.(netboxDefaultError); ok {
+			return apiErr.Code, response.GetPayload(), true
+		}
+		return apiErr.Code, nil, true
+	}
+
+	return 0, nil, false
+}
+
+// getNetboxErrorMessage flattens the error messages Netbox returns for a
+// field, which is usually a list of strings.
+func getNetboxErrorMessage(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		messages := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				messages = append(messages, s)
+			} else {
+				messages = append(messages, getNetboxErrorMessage(item))
+			}
+		}
+		return strings.Join(messages, "\n")
+	default:
+		// Nested errors, e.g. of a single tag, are shown as they are returned
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(encoded)
+	}
+}
+
+// getNetboxErrorSummary returns the summary of the first hint matching the
+// error message, or the given fallback if no hint matches.
+func getNetboxErrorSummary(message string, fallback string) string {
+	for _, hint := range netboxErrorHints {
+		if hint.pattern.MatchString(message) {
+			return hint.summary
+		}
+	}
+	return fallback
+}
+
+// getAttributeForNetboxField maps a Netbox field name to the name of the
+// Terraform attribute holding its value. Related objects are usually
+// referenced by an attribute with an _id suffix, e.g. tenant by tenant_id.
+func getAttributeForNetboxField(field string, resourceSchema map[string]*schema.Schema) (string, bool) {
+	for _, candidate := range []string{field, field + "_id"} {
+		if _, ok := resourceSchema[candidate]; ok {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// netboxPermissionDiagnostic returns a diagnostic for a 403 response, naming
+// the object permission the API token most likely lacks.
+func netboxPermissionDiagnostic(err error, payload interface{}) diag.Diagnostic {
+	detail := ""
+	if fields, ok := payload.(map[string]interface{}); ok {
+		if message, ok := fields["detail"].(string); ok {
+			detail = message
+		}
+	}
+	if detail == "" {
+		detail = err.Error()
+	}
+
+	if permission := getNetboxPermissionForError(err); permission != "" {
+		detail = fmt.Sprintf("%s\n\nMake sure the user owning the API token has the %q permission, e.g. by assigning an object permission with the matching action in the Netbox admin interface.", detail, permission)
+	}
+
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  "Permission denied by Netbox",
+		Detail:   detail,
+	}
+}
+
+// getNetboxPermissionForError derives the name of the Netbox permission that
+// is required for the operation that failed, e.g. "dcim.add_site".
+func getNetboxPermissionForError(err error) string {
+	matches := netboxOperationRegexp.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return ""
+	}
+	method, path := matches[1], matches[2]
+
+	var action string
+	switch method {
+	case http.MethodGet:
+		action = "view"
+	case http.MethodPost:
+		action = "add"
+	case http.MethodPut, http.MethodPatch:
+		action = "change"
+	case http.MethodDelete:
+		action = "delete"
+	default:
+		return ""
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	app, endpoint := parts[0], parts[1]
+
+	model := strings.ReplaceAll(endpoint, "-", "")
+	switch {
+	case app == "virtualization" && endpoint == "interfaces":
+		model = "vminterface"
+	case strings.HasSuffix(model, "sses"), strings.HasSuffix(model, "xes"):
+		model = strings.TrimSuffix(model, "es")
+	default:
+		model = strings.TrimSuffix(model, "s")
+	}
+
+	return fmt.Sprintf("%s.%s_%s", app, action, model)
+}
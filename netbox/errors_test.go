@@ -0,0 +1,173 @@
+package netbox
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/client/ipam"
+	"github.com/go-openapi/runtime"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagFromNetboxErrorValidationErrors(t *testing.T) {
+
+	err := dcim.NewDcimSitesCreateDefault(400)
+	err.Payload = map[string]interface{}{
+		"slug":   []interface{}{"site with this slug already exists."},
+		"tenant": []interface{}{"Invalid pk \"42\" - object does not exist."},
+		"foo":    []interface{}{"This field is required."},
+	}
+
+	diags := diagFromNetboxError(err, resourceNetboxSite().Schema)
+	assert.Len(t, diags, 3)
+
+	assert.Equal(t, `Netbox rejected the value of "foo"`, diags[0].Summary)
+	assert.Nil(t, diags[0].AttributePath)
+
+	assert.Equal(t, "site with this slug already exists.", diags[1].Detail)
+	assert.Equal(t, cty.GetAttrPath("slug"), diags[1].AttributePath)
+
+	assert.Equal(t, `Netbox rejected the value of "tenant_id"`, diags[2].Summary)
+	assert.Equal(t, cty.GetAttrPath("tenant_id"), diags[2].AttributePath)
+}
+
+func TestDiagFromNetboxErrorRackPositionOccupied(t *testing.T) {
+
+	err := dcim.NewDcimDevicesCreateDefault(400)
+	err.Payload = map[string]interface{}{
+		"position": []interface{}{"U10 is already occupied or does not have sufficient space to accommodate this device type: Server (2U)"},
+	}
+
+	diags := diagFromNetboxError(err, resourceNetboxDevice().Schema)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "Rack position is already occupied", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "U10 is already occupied")
+	assert.Equal(t, cty.GetAttrPath("position"), diags[0].AttributePath)
+}
+
+func TestDiagFromNetboxErrorNonFieldErrors(t *testing.T) {
+
+	err := ipam.NewIpamPrefixesDeleteDefault(409)
+	err.Payload = map[string]interface{}{
+		"detail": "Unable to delete object. 1 dependent objects were found: 10.0.0.1/24",
+	}
+
+	diags := diagFromNetboxError(err, resourceNetboxPrefix().Schema)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "Object is still in use by dependent objects", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "10.0.0.1/24")
+	assert.Nil(t, diags[0].AttributePath)
+}
+
+func TestDiagFromNetboxErrorCableAlreadyAttached(t *testing.T) {
+
+	err := dcim.NewDcimCablesCreateDefault(400)
+	err.Payload = map[string]interface{}{
+		"__all__": []interface{}{"Ethernet1/1 already has a cable attached (#12)"},
+	}
+
+	diags := diagFromNetboxError(err, resourceNetboxCable().Schema)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "Cable termination is already connected", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "Ethernet1/1 already has a cable attached")
+	assert.Nil(t, diags[0].AttributePath)
+}
+
+func TestDiagFromNetboxErrorPermissionDenied(t *testing.T) {
+
+	err := dcim.NewDcimSitesCreateDefault(403)
+	err.Payload = map[string]interface{}{
+		"detail": "You do not have permission to perform this action.",
+	}
+
+	diags := diagFromNetboxError(err, resourceNetboxSite().Schema)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "Permission denied by Netbox", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "You do not have permission to perform this action.")
+	assert.Contains(t, diags[0].Detail, `"dcim.add_site"`)
+}
+
+func TestDiagFromNetboxErrorAPIError(t *testing.T) {
+
+	response := dcim.NewDcimSitesCreateDefault(400)
+	response.Payload = map[string]interface{}{
+		"name": []interface{}{"This field is required."},
+	}
+	err := runtime.NewAPIError("unexpected success response", response, 400)
+
+	diags := diagFromNetboxError(err, resourceNetboxSite().Schema)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, cty.GetAttrPath("name"), diags[0].AttributePath)
+}
+
+func TestDiagFromNetboxErrorPassesThroughOtherErrors(t *testing.T) {
+
+	err := errors.New("dial tcp: lookup fake.netbox.server: no such host")
+
+	diags := diagFromNetboxError(err, resourceNetboxSite().Schema)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, err.Error(), diags[0].Summary)
+
+	assert.Nil(t, diagFromNetboxError(nil, resourceNetboxSite().Schema))
+}
+
+func TestGetNetboxErrorMessage(t *testing.T) {
+
+	assert.Equal(t, "foo", getNetboxErrorMessage("foo"))
+	assert.Equal(t, "foo\nbar", getNetboxErrorMessage([]interface{}{"foo", "bar"}))
+	assert.Equal(t, "{}\n{\"name\":[\"invalid\"]}", getNetboxErrorMessage([]interface{}{
+		map[string]interface{}{},
+		map[string]interface{}{"name": []interface{}{"invalid"}},
+	}))
+}
+
+func TestGetNetboxPermissionForError(t *testing.T) {
+
+	for _, tc := range []struct {
+		err      error
+		expected string
+	}{
+		{dcim.NewDcimSitesCreateDefault(403), "dcim.add_site"},
+		{dcim.NewDcimDevicesPartialUpdateDefault(403), "dcim.change_device"},
+		{ipam.NewIpamIPAddressesDeleteDefault(403), "ipam.delete_ipaddress"},
+		{ipam.NewIpamPrefixesReadDefault(403), "ipam.view_prefix"},
+		{errors.New("connection refused"), ""},
+	} {
+		assert.Equal(t, tc.expected, getNetboxPermissionForError(tc.err))
+	}
+}
+
+func TestIsNotFoundError(t *testing.T) {
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"default response 404", dcim.NewDcimDevicesReadDefault(404), true},
+		{"default response 500", dcim.NewDcimDevicesReadDefault(500), false},
+		{"other default response 404", ipam.NewIpamPrefixesReadDefault(404), true},
+		{"api error 404", runtime.NewAPIError("not found", nil, 404), true},
+		{"api error 502", runtime.NewAPIError("bad gateway", nil, 502), false},
+		{"wrapped default response 404", fmt.Errorf("reading device: %w", dcim.NewDcimDevicesReadDefault(404)), true},
+		{"network error", &url.Error{Op: "Get", URL: "https://fake.netbox.server/api/dcim/devices/1/", Err: errors.New("no such host")}, false},
+		{"plain error", errors.New("something went wrong"), false},
+		{"nil", nil, false},
+	} {
+		assert.Equal(t, tc.expected, isNotFoundError(tc.err), tc.name)
+	}
+}
+
+func TestDiagFromNetboxErrorNetworkError(t *testing.T) {
+
+	err := &url.Error{Op: "Get", URL: "https://fake.netbox.server/api/dcim/sites/1/", Err: errors.New("dial tcp: lookup fake.netbox.server: no such host")}
+
+	diags := diagFromNetboxError(err, resourceNetboxSite().Schema)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "Unable to communicate with Netbox", diags[0].Summary)
+	assert.Equal(t, err.Error(), diags[0].Detail)
+}
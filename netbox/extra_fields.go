@@ -0,0 +1,81 @@
+package netbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+)
+
+// withRequestFields returns a client option that adds fields to the JSON body
+// of a request. It is used to send fields that the Netbox API supports, but the
+// models of the generated go-netbox client lack. Fields with a nil value are
+// sent as null, which allows to clear them with a partial update.
+func withRequestFields(fields map[string]interface{}) func(*runtime.ClientOperation) {
+	return func(op *runtime.ClientOperation) {
+		params := op.Params
+		op.Params = runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, registry strfmt.Registry) error {
+			if err := params.WriteToRequest(r, registry); err != nil {
+				return err
+			}
+
+			body := map[string]interface{}{}
+			if payload := r.GetBodyParam(); payload != nil {
+				encoded, err := json.Marshal(payload)
+				if err != nil {
+					return err
+				}
+				if err := json.Unmarshal(encoded, &body); err != nil {
+					return err
+				}
+			}
+			for name, value := range fields {
+				body[name] = value
+			}
+			return r.SetBodyParam(body)
+		})
+	}
+}
+
+// withResponseFields returns a client option that additionally decodes the
+// JSON body of a successful response into target. It is the counterpart of
+// withRequestFields to read fields the generated models lack.
+func withResponseFields(target interface{}) func(*runtime.ClientOperation) {
+	return func(op *runtime.ClientOperation) {
+		op.Reader = responseFieldsReader{reader: op.Reader, target: target}
+	}
+}
+
+// responseFieldsReader decodes the body of a response into target before
+// passing it on to the reader of the generated client.
+type responseFieldsReader struct {
+	reader runtime.ClientResponseReader
+	target interface{}
+}
+
+func (r responseFieldsReader) ReadResponse(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	body, err := io.ReadAll(response.Body())
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Code() >= 200 && response.Code() < 300 && len(body) > 0 {
+		if err := json.Unmarshal(body, r.target); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.reader.ReadResponse(bufferedResponse{ClientResponse: response, body: body}, consumer)
+}
+
+// bufferedResponse is a response whose body has already been read.
+type bufferedResponse struct {
+	runtime.ClientResponse
+	body []byte
+}
+
+func (r bufferedResponse) Body() io.ReadCloser {
+	return io.NopCloser(bytes.NewReader(r.body))
+}
@@ -0,0 +1,69 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	netboxClient "github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// testExtraFieldsClient returns a client whose Netbox records the body of the
+// last request and answers every request with response.
+func testExtraFieldsClient(t *testing.T, response string) (*netboxClient.NetBoxAPI, *map[string]interface{}) {
+	body := map[string]interface{}{}
+
+	state := newTestProviderState(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil {
+			json.NewDecoder(r.Body).Decode(&body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(response))
+	})
+	return state.NetBoxAPI, &body
+}
+
+func TestWithRequestFields(t *testing.T) {
+
+	api, body := testExtraFieldsClient(t, `{"id": 1, "name": "floor-1", "slug": "floor-1"}`)
+
+	name := "floor-1"
+	params := dcim.NewDcimLocationsCreateParams().WithData(&models.WritableLocation{
+		Name: &name,
+		Slug: &name,
+		Site: int64ToPtr(3),
+	})
+	_, err := api.Dcim.DcimLocationsCreate(params, nil, withRequestFields(map[string]interface{}{
+		"status": "planned",
+		"tenant": nil,
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "floor-1", (*body)["name"])
+	assert.Equal(t, float64(3), (*body)["site"])
+	assert.Equal(t, "planned", (*body)["status"])
+	assert.Contains(t, *body, "tenant")
+	assert.Nil(t, (*body)["tenant"])
+}
+
+func TestWithResponseFields(t *testing.T) {
+
+	api, _ := testExtraFieldsClient(t, `{
+		"id": 1,
+		"name": "floor-1",
+		"slug": "floor-1",
+		"status": {"value": "planned", "label": "Planned"},
+		"tenant": {"id": 4, "name": "tenant", "slug": "tenant"}
+	}`)
+
+	var fields locationFields
+	res, err := api.Dcim.DcimLocationsCreate(dcim.NewDcimLocationsCreateParams(), nil, withResponseFields(&fields))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), res.GetPayload().ID)
+	assert.Equal(t, "planned", fields.Status.Value)
+	assert.Equal(t, int64(4), fields.Tenant.ID)
+}
@@ -0,0 +1,97 @@
+package netbox
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// listFilterReservedNames are query parameters used by the paginator, which
+// cannot be set as filter.
+var listFilterReservedNames = map[string]bool{
+	"limit":  true,
+	"offset": true,
+}
+
+// listFilterSchema returns the schema of the filter block of plural data
+// sources.
+func listFilterSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: "Filters passed to Netbox as query parameters. Any filter supported by the Netbox API can be used, including lookup expressions like `name__ic` and custom fields like `cf_environment`. Filters with the same name match any of their values, filters with different names must all match.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"value": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+		},
+	}
+}
+
+// withQueryParams returns a client option that adds query parameters to a
+// request. It is used to pass the filters of plural data sources, which the
+// parameters of the generated go-netbox client only partially cover.
+func withQueryParams(query url.Values) func(*runtime.ClientOperation) {
+	return func(op *runtime.ClientOperation) {
+		params := op.Params
+		op.Params = runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, registry strfmt.Registry) error {
+			if err := params.WriteToRequest(r, registry); err != nil {
+				return err
+			}
+
+			for name, values := range query {
+				if err := r.SetQueryParam(name, values...); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// getListFilter builds the filters of a list request from the filter block of
+// a plural data source. Filters whose Netbox name differs from the name
+// historically accepted by the data source are renamed using aliases.
+func getListFilter(d *schema.ResourceData, aliases map[string]string) (url.Values, error) {
+	filter := make(url.Values)
+
+	filterSet, ok := d.GetOk("filter")
+	if !ok {
+		return filter, nil
+	}
+
+	for _, f := range filterSet.(*schema.Set).List() {
+		name := f.(map[string]interface{})["name"].(string)
+		value := f.(map[string]interface{})["value"].(string)
+
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		if name == "" {
+			return nil, fmt.Errorf("filter name must not be empty")
+		}
+		if listFilterReservedNames[name] {
+			return nil, fmt.Errorf("'%s' is not a supported filter parameter, use the limit attribute instead", name)
+		}
+
+		filter[name] = append(filter[name], value)
+	}
+
+	// The order of a set is arbitrary, sort the values for stable requests
+	for _, values := range filter {
+		sort.Strings(values)
+	}
+
+	return filter, nil
+}
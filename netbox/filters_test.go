@@ -0,0 +1,88 @@
+package netbox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	netboxClient "github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/tenancy"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func testListFilterResourceData(t *testing.T, filters ...map[string]interface{}) *schema.ResourceData {
+	raw := make([]interface{}, len(filters))
+	for i, f := range filters {
+		raw[i] = f
+	}
+	return schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"filter": listFilterSchema(),
+	}, map[string]interface{}{
+		"filter": raw,
+	})
+}
+
+func TestGetListFilter(t *testing.T) {
+
+	d := testListFilterResourceData(t,
+		map[string]interface{}{"name": "status", "value": "active"},
+		map[string]interface{}{"name": "name__ic", "value": "web"},
+		map[string]interface{}{"name": "status", "value": "planned"},
+		map[string]interface{}{"name": "cf_environment", "value": "production"},
+		map[string]interface{}{"name": "vm_id", "value": "42"},
+	)
+
+	filter, err := getListFilter(d, map[string]string{"vm_id": "virtual_machine_id"})
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"status":             {"active", "planned"},
+		"name__ic":           {"web"},
+		"cf_environment":     {"production"},
+		"virtual_machine_id": {"42"},
+	}, filter)
+}
+
+func TestGetListFilterRejectsPaginationParameters(t *testing.T) {
+
+	d := testListFilterResourceData(t, map[string]interface{}{"name": "limit", "value": "10"})
+
+	_, err := getListFilter(d, nil)
+	assert.Error(t, err)
+}
+
+func TestWithQueryParams(t *testing.T) {
+
+	var received map[string][]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": 0, "results": []}`))
+	}))
+	defer ts.Close()
+
+	config := Config{
+		APIToken:  "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL: ts.URL,
+	}
+
+	client, err := config.Client()
+	assert.NoError(t, err)
+
+	limit := int64(50)
+	params := tenancy.NewTenancyTenantsListParams()
+	params.Limit = &limit
+	query := url.Values{
+		"group":   {"a", "b"},
+		"cf_site": {"x"},
+	}
+
+	_, err = client.(*netboxClient.NetBoxAPI).Tenancy.TenancyTenantsList(params, nil, withQueryParams(query))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"group":   {"a", "b"},
+		"cf_site": {"x"},
+		"limit":   {"50"},
+	}, received)
+}
@@ -0,0 +1,335 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/client/extras"
+	"github.com/fbreckle/go-netbox/netbox/client/ipam"
+	"github.com/fbreckle/go-netbox/netbox/client/tenancy"
+	"github.com/fbreckle/go-netbox/netbox/client/virtualization"
+	"github.com/go-openapi/runtime"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// naturalKeyLookupFunc returns the IDs of all objects matching a natural key.
+type naturalKeyLookupFunc func(ctx context.Context, api *providerState, key string) ([]int64, error)
+
+// naturalKeyImporter returns an importer that accepts the numeric ID of an
+// object or a natural key, e.g. its slug, described by keyFormat. A natural
+// key must match exactly one object.
+func naturalKeyImporter(objectType string, keyFormat string, lookup naturalKeyLookupFunc) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+			key := d.Id()
+			if _, err := strconv.ParseInt(key, 10, 64); err == nil {
+				return []*schema.ResourceData{d}, nil
+			}
+
+			ids, err := lookup(ctx, m.(*providerState), key)
+			if err != nil {
+				return nil, err
+			}
+
+			switch len(ids) {
+			case 0:
+				return nil, fmt.Errorf("no %s found for %q, expected a numeric ID or %s", objectType, key, keyFormat)
+			case 1:
+				d.SetId(strconv.FormatInt(ids[0], 10))
+				return []*schema.ResourceData{d}, nil
+			default:
+				return nil, fmt.Errorf("%q matches %d objects of type %s (IDs %s), import by a more specific key or by numeric ID", key, len(ids), objectType, joinIDs(ids))
+			}
+		},
+	}
+}
+
+// splitNaturalKey splits a natural key like "10.0.0.0/24@production" into
+// the key of the object and the key of its parent. The parent is empty if the
+// natural key has no @.
+func splitNaturalKey(key string) (string, string) {
+	if i := strings.LastIndex(key, "@"); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return key, ""
+}
+
+// joinIDs formats a list of IDs for error messages.
+func joinIDs(ids []int64) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(s, ", ")
+}
+
+// resolveSingleID returns the only ID in ids, or an error explaining why the
+// parent referenced in a natural key could not be resolved.
+func resolveSingleID(objectType string, key string, ids []int64) (string, error) {
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no %s found with name %q", objectType, key)
+	case 1:
+		return strconv.FormatInt(ids[0], 10), nil
+	default:
+		return "", fmt.Errorf("%d objects of type %s are named %q (IDs %s)", len(ids), objectType, key, joinIDs(ids))
+	}
+}
+
+// slugListFunc lists the objects with the given slug, passing option to the
+// client.
+type slugListFunc func(ctx context.Context, api *providerState, slug string, option func(*runtime.ClientOperation)) error
+
+// lookupBySlug returns a lookup function for objects whose natural key is
+// their slug. The IDs are decoded from the raw response, so that list does not
+// need to know the model of the objects.
+func lookupBySlug(list slugListFunc) naturalKeyLookupFunc {
+	return func(ctx context.Context, api *providerState, slug string) ([]int64, error) {
+		var fields struct {
+			Results []struct {
+				ID int64 `json:"id"`
+			} `json:"results"`
+		}
+		if err := list(ctx, api, slug, withResponseFields(&fields)); err != nil {
+			return nil, err
+		}
+
+		var ids []int64
+		for _, result := range fields.Results {
+			ids = append(ids, result.ID)
+		}
+		return ids, nil
+	}
+}
+
+var lookupSitesBySlug = lookupBySlug(func(ctx context.Context, api *providerState, slug string, option func(*runtime.ClientOperation)) error {
+	_, err := api.Dcim.DcimSitesList(dcim.NewDcimSitesListParamsWithContext(ctx).WithSlug(&slug), nil, option)
+	return err
+})
+
+var lookupSiteGroupsBySlug = lookupBySlug(func(ctx context.Context, api *providerState, slug string, option func(*runtime.ClientOperation)) error {
+	_, err := api.Dcim.DcimSiteGroupsList(dcim.NewDcimSiteGroupsListParamsWithContext(ctx).WithSlug(&slug), nil, option)
+	return err
+})
+
+var lookupTenantsBySlug = lookupBySlug(func(ctx context.Context, api *providerState, slug string, option func(*runtime.ClientOperation)) error {
+	_, err := api.Tenancy.TenancyTenantsList(tenancy.NewTenancyTenantsListParamsWithContext(ctx).WithSlug(&slug), nil, option)
+	return err
+})
+
+var lookupTagsBySlug = lookupBySlug(func(ctx context.Context, api *providerState, slug string, option func(*runtime.ClientOperation)) error {
+	_, err := api.Extras.ExtrasTagsList(extras.NewExtrasTagsListParamsWithContext(ctx).WithSlug(&slug), nil, option)
+	return err
+})
+
+var lookupPlatformsBySlug = lookupBySlug(func(ctx context.Context, api *providerState, slug string, option func(*runtime.ClientOperation)) error {
+	_, err := api.Dcim.DcimPlatformsList(dcim.NewDcimPlatformsListParamsWithContext(ctx).WithSlug(&slug), nil, option)
+	return err
+})
+
+var lookupDeviceRolesBySlug = lookupBySlug(func(ctx context.Context, api *providerState, slug string, option func(*runtime.ClientOperation)) error {
+	_, err := api.Dcim.DcimDeviceRolesList(dcim.NewDcimDeviceRolesListParamsWithContext(ctx).WithSlug(&slug), nil, option)
+	return err
+})
+
+var lookupRackRolesBySlug = lookupBySlug(func(ctx context.Context, api *providerState, slug string, option func(*runtime.ClientOperation)) error {
+	_, err := api.Dcim.DcimRackRolesList(dcim.NewDcimRackRolesListParamsWithContext(ctx).WithSlug(&slug), nil, option)
+	return err
+})
+
+var lookupIpamRolesBySlug = lookupBySlug(func(ctx context.Context, api *providerState, slug string, option func(*runtime.ClientOperation)) error {
+	_, err := api.Ipam.IpamRolesList(ipam.NewIpamRolesListParamsWithContext(ctx).WithSlug(&slug), nil, option)
+	return err
+})
+
+// lookupVirtualMachinesByName resolves "name" or "name@cluster".
+func lookupVirtualMachinesByName(ctx context.Context, api *providerState, key string) ([]int64, error) {
+	name, clusterName := splitNaturalKey(key)
+
+	params := virtualization.NewVirtualizationVirtualMachinesListParamsWithContext(ctx)
+	params.Name = &name
+
+	if clusterName != "" {
+		clusterParams := virtualization.NewVirtualizationClustersListParamsWithContext(ctx)
+		clusterParams.Name = &clusterName
+
+		res, err := api.Virtualization.VirtualizationClustersList(clusterParams, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var clusterIDs []int64
+		for _, cluster := range res.GetPayload().Results {
+			clusterIDs = append(clusterIDs, cluster.ID)
+		}
+		clusterID, err := resolveSingleID("netbox_cluster", clusterName, clusterIDs)
+		if err != nil {
+			return nil, err
+		}
+		params.ClusterID = &clusterID
+	}
+
+	res, err := api.Virtualization.VirtualizationVirtualMachinesList(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, vm := range res.GetPayload().Results {
+		ids = append(ids, vm.ID)
+	}
+	return ids, nil
+}
+
+// lookupVrfIDByName resolves the VRF part of a natural key.
+func lookupVrfIDByName(ctx context.Context, api *providerState, name string) (string, error) {
+	params := ipam.NewIpamVrfsListParamsWithContext(ctx)
+	params.Name = &name
+
+	res, err := api.Ipam.IpamVrfsList(params, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var ids []int64
+	for _, vrf := range res.GetPayload().Results {
+		ids = append(ids, vrf.ID)
+	}
+	return resolveSingleID("netbox_vrf", name, ids)
+}
+
+// lookupPrefixesByCIDR resolves "cidr" or "cidr@vrf".
+func lookupPrefixesByCIDR(ctx context.Context, api *providerState, key string) ([]int64, error) {
+	cidr, vrfName := splitNaturalKey(key)
+
+	params := ipam.NewIpamPrefixesListParamsWithContext(ctx)
+	params.Prefix = &cidr
+
+	if vrfName != "" {
+		vrfID, err := lookupVrfIDByName(ctx, api, vrfName)
+		if err != nil {
+			return nil, err
+		}
+		params.VrfID = &vrfID
+	}
+
+	res, err := api.Ipam.IpamPrefixesList(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, prefix := range res.GetPayload().Results {
+		ids = append(ids, prefix.ID)
+	}
+	return ids, nil
+}
+
+// lookupIPAddressesByAddress resolves "address" or "address@vrf".
+func lookupIPAddressesByAddress(ctx context.Context, api *providerState, key string) ([]int64, error) {
+	address, vrfName := splitNaturalKey(key)
+
+	params := ipam.NewIpamIPAddressesListParamsWithContext(ctx)
+	params.Address = &address
+
+	if vrfName != "" {
+		vrfID, err := lookupVrfIDByName(ctx, api, vrfName)
+		if err != nil {
+			return nil, err
+		}
+		params.VrfID = &vrfID
+	}
+
+	res, err := api.Ipam.IpamIPAddressesList(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, ipAddress := range res.GetPayload().Results {
+		ids = append(ids, ipAddress.ID)
+	}
+	return ids, nil
+}
+
+// lookupVlansByVid resolves "vid@site", where site is the slug of the site of
+// the VLAN, or "vid:vid" for a VLAN ID that is unique without a site. A bare
+// number is the numeric ID of the object, so a VLAN ID without a site needs the
+// prefix.
+func lookupVlansByVid(ctx context.Context, api *providerState, key string) ([]int64, error) {
+	vid, site := splitNaturalKey(strings.TrimPrefix(key, "vid:"))
+	if site == "" && !strings.HasPrefix(key, "vid:") {
+		return nil, fmt.Errorf("invalid VLAN key %q, expected vid@site or vid:vid", key)
+	}
+	if _, err := strconv.Atoi(vid); err != nil {
+		return nil, fmt.Errorf("invalid VLAN ID %q in %q", vid, key)
+	}
+
+	params := ipam.NewIpamVlansListParamsWithContext(ctx)
+	params.Vid = &vid
+	if site != "" {
+		params.Site = &site
+	}
+
+	res, err := api.Ipam.IpamVlansList(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, vlan := range res.GetPayload().Results {
+		ids = append(ids, vlan.ID)
+	}
+	return ids, nil
+}
+
+// lookupLocationsBySlug resolves "slug" or "slug@site", where site is the slug
+// of the site of the location. Location slugs are only unique within a site.
+func lookupLocationsBySlug(ctx context.Context, api *providerState, key string) ([]int64, error) {
+	slug, site := splitNaturalKey(key)
+
+	params := dcim.NewDcimLocationsListParamsWithContext(ctx)
+	params.Slug = &slug
+	if site != "" {
+		params.Site = &site
+	}
+
+	res, err := api.Dcim.DcimLocationsList(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, location := range res.GetPayload().Results {
+		ids = append(ids, location.ID)
+	}
+	return ids, nil
+}
+
+// lookupDeviceInterfacesByName resolves "device/interface-name". Interface
+// names often contain slashes, so the key is split at the first one.
+func lookupDeviceInterfacesByName(ctx context.Context, api *providerState, key string) ([]int64, error) {
+	i := strings.Index(key, "/")
+	if i <= 0 || i == len(key)-1 {
+		return nil, fmt.Errorf("invalid interface key %q, expected device/interface-name", key)
+	}
+	device, name := key[:i], key[i+1:]
+
+	params := dcim.NewDcimInterfacesListParamsWithContext(ctx)
+	params.Device = &device
+	params.Name = &name
+
+	res, err := api.Dcim.DcimInterfacesList(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, iface := range res.GetPayload().Results {
+		ids = append(ids, iface.ID)
+	}
+	return ids, nil
+}
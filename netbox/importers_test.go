@@ -0,0 +1,172 @@
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newImporterTestState returns a provider state talking to a fake Netbox that
+// answers list requests with objects of the given IDs, depending on the path
+// and query string of the request.
+func newImporterTestState(t *testing.T, handler func(path string, query map[string][]string) []int64) *providerState {
+	return newTestProviderState(t, func(w http.ResponseWriter, r *http.Request) {
+		var results []map[string]interface{}
+		for _, id := range handler(r.URL.Path, r.URL.Query()) {
+			results = append(results, map[string]interface{}{"id": id})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count":   len(results),
+			"results": results,
+		})
+	})
+}
+
+func importTestResource(t *testing.T, resourceType string, id string, api *providerState) (string, error) {
+	r := Provider().ResourcesMap[resourceType]
+	d := r.TestResourceData()
+	d.SetId(id)
+
+	result, err := r.Importer.StateContext(context.Background(), d, api)
+	if err != nil {
+		return "", err
+	}
+	return result[0].Id(), nil
+}
+
+func TestNaturalKeyImporterBySlug(t *testing.T) {
+
+	api := newImporterTestState(t, func(path string, query map[string][]string) []int64 {
+		assert.Equal(t, "/api/dcim/sites/", path)
+		switch query["slug"][0] {
+		case "dc-frankfurt":
+			return []int64{7}
+		}
+		return nil
+	})
+
+	id, err := importTestResource(t, "netbox_site", "dc-frankfurt", api)
+	assert.NoError(t, err)
+	assert.Equal(t, "7", id)
+
+	_, err = importTestResource(t, "netbox_site", "dc-unknown", api)
+	assert.ErrorContains(t, err, `no netbox_site found for "dc-unknown"`)
+}
+
+func TestNaturalKeyImporterByNumericID(t *testing.T) {
+
+	api := newImporterTestState(t, func(path string, query map[string][]string) []int64 {
+		t.Errorf("unexpected request to %s", path)
+		return nil
+	})
+
+	id, err := importTestResource(t, "netbox_tenant", "42", api)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", id)
+}
+
+func TestNaturalKeyImporterAmbiguous(t *testing.T) {
+
+	api := newImporterTestState(t, func(path string, query map[string][]string) []int64 {
+		assert.Equal(t, "/api/ipam/prefixes/", path)
+		assert.Equal(t, []string{"10.0.0.0/24"}, query["prefix"])
+		return []int64{3, 4}
+	})
+
+	_, err := importTestResource(t, "netbox_prefix", "10.0.0.0/24", api)
+	assert.ErrorContains(t, err, "matches 2 objects of type netbox_prefix (IDs 3, 4)")
+}
+
+func TestNaturalKeyImporterWithParent(t *testing.T) {
+
+	api := newImporterTestState(t, func(path string, query map[string][]string) []int64 {
+		switch path {
+		case "/api/ipam/vrfs/":
+			assert.Equal(t, []string{"production"}, query["name"])
+			return []int64{5}
+		case "/api/ipam/ip-addresses/":
+			assert.Equal(t, []string{"10.0.0.1/24"}, query["address"])
+			assert.Equal(t, []string{"5"}, query["vrf_id"])
+			return []int64{9}
+		case "/api/virtualization/clusters/":
+			return []int64{1, 2}
+		}
+		t.Errorf("unexpected request to %s", path)
+		return nil
+	})
+
+	id, err := importTestResource(t, "netbox_ip_address", "10.0.0.1/24@production", api)
+	assert.NoError(t, err)
+	assert.Equal(t, "9", id)
+
+	_, err = importTestResource(t, "netbox_virtual_machine", "web01@production", api)
+	assert.ErrorContains(t, err, `2 objects of type netbox_cluster are named "production" (IDs 1, 2)`)
+}
+
+func TestNaturalKeyImporterDeviceInterface(t *testing.T) {
+
+	api := newImporterTestState(t, func(path string, query map[string][]string) []int64 {
+		assert.Equal(t, "/api/dcim/interfaces/", path)
+		assert.Equal(t, []string{"sw01"}, query["device"])
+		assert.Equal(t, []string{"Ethernet1/1"}, query["name"])
+		return []int64{21}
+	})
+
+	id, err := importTestResource(t, "netbox_device_interface", "sw01/Ethernet1/1", api)
+	assert.NoError(t, err)
+	assert.Equal(t, "21", id)
+
+	_, err = importTestResource(t, "netbox_device_interface", "Ethernet1", api)
+	assert.ErrorContains(t, err, "expected device/interface-name")
+}
+
+func TestNaturalKeyImporterVlan(t *testing.T) {
+
+	api := newImporterTestState(t, func(path string, query map[string][]string) []int64 {
+		assert.Equal(t, "/api/ipam/vlans/", path)
+		assert.Equal(t, []string{"100"}, query["vid"])
+		if len(query["site"]) > 0 {
+			assert.Equal(t, []string{"dc-frankfurt"}, query["site"])
+			return []int64{13}
+		}
+		return []int64{12}
+	})
+
+	// A bare number is the ID of the object, not the VLAN ID
+	id, err := importTestResource(t, "netbox_vlan", "100", api)
+	assert.NoError(t, err)
+	assert.Equal(t, "100", id)
+
+	id, err = importTestResource(t, "netbox_vlan", "vid:100", api)
+	assert.NoError(t, err)
+	assert.Equal(t, "12", id)
+
+	id, err = importTestResource(t, "netbox_vlan", "vid:100@dc-frankfurt", api)
+	assert.NoError(t, err)
+	assert.Equal(t, "13", id)
+
+	id, err = importTestResource(t, "netbox_vlan", "100@dc-frankfurt", api)
+	assert.NoError(t, err)
+	assert.Equal(t, "13", id)
+
+	_, err = importTestResource(t, "netbox_vlan", "native", api)
+	assert.ErrorContains(t, err, "expected vid@site or vid:vid")
+
+	_, err = importTestResource(t, "netbox_vlan", "vid:native", api)
+	assert.ErrorContains(t, err, `invalid VLAN ID "native"`)
+}
+
+func TestSplitNaturalKey(t *testing.T) {
+
+	key, parent := splitNaturalKey("100@dc-frankfurt")
+	assert.Equal(t, "100", key)
+	assert.Equal(t, "dc-frankfurt", parent)
+
+	key, parent = splitNaturalKey("10.0.0.0/24")
+	assert.Equal(t, "10.0.0.0/24", key)
+	assert.Equal(t, "", parent)
+}
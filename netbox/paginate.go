@@ -0,0 +1,116 @@
+package netbox
+
+import (
+	"sync"
+)
+
+// paginationPageSize is the number of objects requested per page. Netbox
+// caps it at its MAX_PAGE_SIZE setting, which defaults to 1000.
+const paginationPageSize = int64(1000)
+
+// paginationConcurrency is the maximum number of pages fetched at the same
+// time.
+const paginationConcurrency = 4
+
+// listPage is a single page of a list response.
+type listPage[T any] struct {
+	results []T
+	// count is the total number of objects matching the request, if known.
+	count *int64
+	// hasNext is true if Netbox returned a link to the next page.
+	hasNext bool
+}
+
+// listPageFunc fetches the page of at most limit objects starting at offset.
+type listPageFunc[T any] func(limit int64, offset int64) (listPage[T], error)
+
+// listAll fetches all objects of a list endpoint, but at most limit objects
+// if limit is greater than 0. Once the first page tells the total number of
+// objects, the remaining pages are fetched concurrently. Otherwise, the pages
+// are fetched one after another until Netbox returns no next page.
+func listAll[T any](fetch listPageFunc[T], limit int64) ([]T, error) {
+	pageSize := paginationPageSize
+	if limit > 0 && limit < pageSize {
+		pageSize = limit
+	}
+
+	first, err := fetch(pageSize, 0)
+	if err != nil {
+		return nil, err
+	}
+	results := first.results
+
+	if !first.hasNext || len(first.results) == 0 || (limit > 0 && int64(len(results)) >= limit) {
+		return truncateResults(results, limit), nil
+	}
+
+	// Netbox returns fewer objects than requested if the page size exceeds
+	// its MAX_PAGE_SIZE setting
+	pageSize = int64(len(first.results))
+
+	if first.count == nil {
+		return listRemainingSequentially(fetch, results, pageSize, limit)
+	}
+
+	total := *first.count
+	if limit > 0 && limit < total {
+		total = limit
+	}
+
+	var offsets []int64
+	for offset := pageSize; offset < total; offset += pageSize {
+		offsets = append(offsets, offset)
+	}
+
+	pages := make([][]T, len(offsets))
+	errs := make([]error, len(offsets))
+	semaphore := make(chan struct{}, paginationConcurrency)
+
+	var wg sync.WaitGroup
+	for i, offset := range offsets {
+		wg.Add(1)
+		go func(i int, offset int64) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			page, err := fetch(pageSize, offset)
+			pages[i] = page.results
+			errs[i] = err
+		}(i, offset)
+	}
+	wg.Wait()
+
+	for i := range pages {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		results = append(results, pages[i]...)
+	}
+
+	return truncateResults(results, limit), nil
+}
+
+// listRemainingSequentially follows the pagination after the first page until
+// Netbox returns no next page or limit objects have been fetched.
+func listRemainingSequentially[T any](fetch listPageFunc[T], results []T, pageSize int64, limit int64) ([]T, error) {
+	for {
+		page, err := fetch(pageSize, int64(len(results)))
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page.results...)
+
+		if !page.hasNext || len(page.results) == 0 || (limit > 0 && int64(len(results)) >= limit) {
+			return truncateResults(results, limit), nil
+		}
+	}
+}
+
+// truncateResults returns at most limit results if limit is greater than 0.
+func truncateResults[T any](results []T, limit int64) []T {
+	if limit > 0 && int64(len(results)) > limit {
+		return results[:limit]
+	}
+	return results
+}
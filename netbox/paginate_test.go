@@ -0,0 +1,116 @@
+package netbox
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeListEndpoint simulates a Netbox list endpoint returning the numbers
+// 0 to total-1, with pages of at most maxPageSize objects.
+type fakeListEndpoint struct {
+	total       int64
+	maxPageSize int64
+	omitCount   bool
+
+	mu       sync.Mutex
+	requests [][2]int64
+}
+
+func (e *fakeListEndpoint) fetch(limit int64, offset int64) (listPage[int64], error) {
+	e.mu.Lock()
+	e.requests = append(e.requests, [2]int64{limit, offset})
+	e.mu.Unlock()
+
+	if limit > e.maxPageSize {
+		limit = e.maxPageSize
+	}
+	var results []int64
+	for i := offset; i < offset+limit && i < e.total; i++ {
+		results = append(results, i)
+	}
+
+	page := listPage[int64]{
+		results: results,
+		hasNext: offset+limit < e.total,
+	}
+	if !e.omitCount {
+		total := e.total
+		page.count = &total
+	}
+	return page, nil
+}
+
+func expectedRange(n int64) []int64 {
+	result := make([]int64, n)
+	for i := range result {
+		result[i] = int64(i)
+	}
+	return result
+}
+
+func TestListAllFetchesAllPages(t *testing.T) {
+
+	endpoint := &fakeListEndpoint{total: 2500, maxPageSize: 1000}
+
+	results, err := listAll(endpoint.fetch, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRange(2500), results)
+	assert.Len(t, endpoint.requests, 3)
+}
+
+func TestListAllHonorsMaxPageSize(t *testing.T) {
+
+	endpoint := &fakeListEndpoint{total: 120, maxPageSize: 50}
+
+	results, err := listAll(endpoint.fetch, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRange(120), results)
+	assert.Len(t, endpoint.requests, 3)
+	for _, request := range endpoint.requests[1:] {
+		assert.Equal(t, int64(50), request[0])
+	}
+}
+
+func TestListAllHonorsLimit(t *testing.T) {
+
+	endpoint := &fakeListEndpoint{total: 120, maxPageSize: 50}
+
+	results, err := listAll(endpoint.fetch, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRange(10), results)
+	assert.Equal(t, [][2]int64{{10, 0}}, endpoint.requests)
+
+	endpoint = &fakeListEndpoint{total: 120, maxPageSize: 50}
+
+	results, err = listAll(endpoint.fetch, 75)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRange(75), results)
+	assert.Len(t, endpoint.requests, 2)
+}
+
+func TestListAllWithoutCount(t *testing.T) {
+
+	endpoint := &fakeListEndpoint{total: 120, maxPageSize: 50, omitCount: true}
+
+	results, err := listAll(endpoint.fetch, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRange(120), results)
+	assert.Equal(t, [][2]int64{{1000, 0}, {50, 50}, {50, 100}}, endpoint.requests)
+}
+
+func TestListAllReturnsErrors(t *testing.T) {
+
+	endpoint := &fakeListEndpoint{total: 120, maxPageSize: 50}
+	fetchErr := errors.New("bad gateway")
+
+	_, err := listAll(func(limit int64, offset int64) (listPage[int64], error) {
+		if offset > 0 {
+			return listPage[int64]{}, fetchErr
+		}
+		return endpoint.fetch(limit, offset)
+	}, 0)
+	assert.ErrorIs(t, err, fetchErr)
+}
@@ -3,15 +3,56 @@ package netbox
 import (
 	"context"
 	"fmt"
-	"strings"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/status"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"golang.org/x/exp/slices"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// providerState is the meta passed to every resource and data source. It
+// embeds the Netbox API client and holds state shared between resources.
+type providerState struct {
+	*client.NetBoxAPI
+	tagCache *tagCache
+
+	// customFieldCache holds the types of the custom fields defined in Netbox.
+	customFieldCache *customFieldCache
+
+	// autoCreateTags makes resources create tags that do not exist in Netbox
+	// instead of failing.
+	autoCreateTags bool
+
+	// ignoreUnmanagedCustomFields makes resources only track the custom
+	// fields that are configured in their custom_fields attribute.
+	ignoreUnmanagedCustomFields bool
+
+	// defaultTags are added to the tags of every resource supporting tags.
+	defaultTags []interface{}
+
+	// defaultTenantID is used as tenant_id by resources that do not set one.
+	// It is 0 if no default tenant is configured.
+	defaultTenantID int64
+
+	// netboxVersion is the version of the Netbox server. It is nil if the
+	// version check was skipped.
+	netboxVersion *semver.Version
+
+	// readOnly makes resources refuse to create, update or delete objects.
+	readOnly bool
+}
+
+func newProviderState(api *client.NetBoxAPI) *providerState {
+	return &providerState{
+		NetBoxAPI:        api,
+		tagCache:         newTagCache(api),
+		customFieldCache: newCustomFieldCache(api),
+	}
+}
+
 // Provider returns a schema.Provider for Netbox.
 func Provider() *schema.Provider {
 	provider := &schema.Provider{
@@ -50,6 +91,12 @@ func Provider() *schema.Provider {
 			"netbox_user":                 resourceNetboxUser(),
 			"netbox_token":                resourceNetboxToken(),
 			"netbox_custom_field":         resourceCustomField(),
+			"netbox_rack":                 resourceNetboxRack(),
+			"netbox_rack_role":            resourceNetboxRackRole(),
+			"netbox_location":             resourceNetboxLocation(),
+			"netbox_site_group":           resourceNetboxSiteGroup(),
+			"netbox_device_interface":     resourceNetboxDeviceInterface(),
+			"netbox_cable":                resourceNetboxCable(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"netbox_cluster":          dataSourceNetboxCluster(),
@@ -69,6 +116,10 @@ func Provider() *schema.Provider {
 			"netbox_ip_addresses":     dataSourceNetboxIpAddresses(),
 			"netbox_ip_range":         dataSourceNetboxIpRange(),
 			"netbox_region":           dataSourceNetboxRegion(),
+			"netbox_status":           dataSourceNetboxStatus(),
+			"netbox_location":         dataSourceNetboxLocation(),
+			"netbox_site_group":       dataSourceNetboxSiteGroup(),
+			"netbox_sites":            dataSourceNetboxSites(),
 		},
 		Schema: map[string]*schema.Schema{
 			"server_url": {
@@ -78,10 +129,25 @@ func Provider() *schema.Provider {
 				Description: "Location of Netbox server including scheme and optional port",
 			},
 			"api_token": {
-				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("NETBOX_API_TOKEN", nil),
-				Description: "Netbox API authentication token",
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("NETBOX_API_TOKEN", nil),
+				ConflictsWith: []string{"api_token_file", "api_token_command"},
+				Description:   "Netbox API authentication token. One of `api_token`, `api_token_file` and `api_token_command` is required.",
+			},
+			"api_token_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("NETBOX_API_TOKEN_FILE", nil),
+				ConflictsWith: []string{"api_token", "api_token_command"},
+				Description:   "Path to a file containing the Netbox API authentication token. The file is read again if Netbox rejects the token, e.g. after it was rotated.",
+			},
+			"api_token_command": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("NETBOX_API_TOKEN_COMMAND", nil),
+				ConflictsWith: []string{"api_token", "api_token_file"},
+				Description:   "Command run with the system shell that prints the Netbox API authentication token. The command is run again if Netbox rejects the token, e.g. after it was rotated.",
 			},
 			"allow_insecure_https": {
 				Type:        schema.TypeBool,
@@ -89,6 +155,85 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("NETBOX_ALLOW_INSECURE_HTTPS", false),
 				Description: "Flag to set whether to allow https with invalid certificates",
 			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("NETBOX_CA_CERT_FILE", nil),
+				ConflictsWith: []string{"ca_cert_pem"},
+				Description:   "Path to a PEM encoded CA certificate bundle used to verify the certificate of the Netbox server, in addition to the system's trusted CAs. Conflicts with `ca_cert_pem`.",
+			},
+			"ca_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("NETBOX_CA_CERT_PEM", nil),
+				ConflictsWith: []string{"ca_cert_file"},
+				Description:   "PEM encoded CA certificate bundle used to verify the certificate of the Netbox server, in addition to the system's trusted CAs. Conflicts with `ca_cert_file`.",
+			},
+			"client_cert_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NETBOX_CLIENT_CERT_FILE", nil),
+				RequiredWith: []string{"client_key_file"},
+				Description:  "Path to a PEM encoded certificate used to authenticate the provider to Netbox via mutual TLS. Requires `client_key_file`.",
+			},
+			"client_key_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NETBOX_CLIENT_KEY_FILE", nil),
+				RequiredWith: []string{"client_cert_file"},
+				Description:  "Path to the PEM encoded private key of `client_cert_file`.",
+			},
+			"http_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NETBOX_HTTP_PROXY", nil),
+				Description: "URL of a proxy to send all requests to Netbox through. If not set, the proxy is taken from the `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables.",
+			},
+			"no_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NETBOX_NO_PROXY", nil),
+				Description: "Comma-separated list of hosts, domains and CIDRs that are reached without `http_proxy`.",
+			},
+			"request_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NETBOX_REQUEST_TIMEOUT", 30),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Time in seconds after which a single request to Netbox is aborted. Retries get a new timeout. Set to 0 to disable the timeout.",
+			},
+			"max_idle_connections": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NETBOX_MAX_IDLE_CONNECTIONS", 10),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of idle connections to Netbox that are kept open for reuse.",
+			},
+			"idle_connection_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NETBOX_IDLE_CONNECTION_TIMEOUT", 90),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Time in seconds after which an idle connection to Netbox is closed.",
+			},
+			"disable_keep_alives": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NETBOX_DISABLE_KEEP_ALIVES", false),
+				Description: "If true, open a new connection to Netbox for every request instead of reusing idle connections.",
+			},
+			"debug_http": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NETBOX_DEBUG_HTTP", false),
+				Description: "If true, log every request to Netbox and its response, including JSON bodies, at info level. Secrets such as the API token, token keys and passwords are redacted. Requests are also logged at debug level if `TF_LOG` is `DEBUG` or `TRACE`.",
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NETBOX_READ_ONLY", false),
+				Description: "If true, the provider refuses every request that could modify Netbox. Resources fail to create, update or delete objects, while data sources and refreshing resources keep working. Useful to run `terraform plan` with a token that has write permissions.",
+			},
 			"headers": {
 				Type:        schema.TypeMap,
 				Optional:    true,
@@ -101,9 +246,86 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("NETBOX_SKIP_VERSION_CHECK", false),
 				Description: "If true, do not try to determine the running Netbox version at provider startup. Disables warnings about possibly unsupported Netbox version. Also useful for local testing on terraform plans.",
 			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NETBOX_MAX_RETRIES", 3),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of times a request to Netbox is retried after a transient error (connection error, HTTP 429, 502, 503 or 504). POST requests are only retried on HTTP 429. Set to 0 to disable retries.",
+			},
+			"retry_wait_min": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NETBOX_RETRY_WAIT_MIN", 1),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Minimum time in seconds to wait before retrying a request. The wait time doubles with every retry. A `Retry-After` header sent by Netbox takes precedence.",
+			},
+			"retry_wait_max": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NETBOX_RETRY_WAIT_MAX", 30),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum time in seconds to wait before retrying a request, which also caps the wait time requested by a `Retry-After` header.",
+			},
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NETBOX_REQUESTS_PER_SECOND", 0.0),
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "Maximum number of requests per second sent to Netbox, shared by all resources and data sources. Set to 0 for no limit.",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NETBOX_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of requests in flight to Netbox at the same time, independent of Terraform's `-parallelism`. Set to 0 for no limit.",
+			},
+			"auto_create_tags": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NETBOX_AUTO_CREATE_TAGS", false),
+				Description: "If true, tags referenced in the `tags` attribute of a resource that do not exist in Netbox are created with a slug derived from their name and the default color. Otherwise, unknown tags are an error.",
+			},
+			"ignore_unmanaged_custom_fields": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NETBOX_IGNORE_UNMANAGED_CUSTOM_FIELDS", false),
+				Description: "If true, the `custom_fields` attribute of a resource only tracks the custom fields that are configured on it, so that custom fields set by other tools do not show up as a diff. All custom fields are available in the `custom_fields_all` attribute.",
+			},
+			"default_tenant": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NETBOX_DEFAULT_TENANT", nil),
+				Description: "ID or slug of a tenant that is assigned to every resource with a `tenant_id` attribute that does not set one.",
+			},
+			"default_tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Configuration block with tags that are added to every resource supporting tags. The tags of a resource including the default tags are exposed in its `tags_all` attribute.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type: schema.TypeSet,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Optional:    true,
+							Set:         schema.HashString,
+							Description: "Names or slugs of the tags to add to every resource.",
+						},
+					},
+				},
+			},
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
+
+	for resourceType, r := range provider.ResourcesMap {
+		guardReadOnly(resourceType, r)
+	}
+
 	return provider
 }
 
@@ -112,10 +334,59 @@ func providerConfigure(ctx context.Context, data *schema.ResourceData) (interfac
 	var diags diag.Diagnostics
 
 	config := Config{
-		ServerURL:          data.Get("server_url").(string),
-		APIToken:           data.Get("api_token").(string),
-		AllowInsecureHttps: data.Get("allow_insecure_https").(bool),
-		Headers:            data.Get("headers").(map[string]interface{}),
+		ServerURL:             data.Get("server_url").(string),
+		APIToken:              data.Get("api_token").(string),
+		AllowInsecureHttps:    data.Get("allow_insecure_https").(bool),
+		CACertFile:            data.Get("ca_cert_file").(string),
+		CACertPEM:             data.Get("ca_cert_pem").(string),
+		ClientCertFile:        data.Get("client_cert_file").(string),
+		ClientKeyFile:         data.Get("client_key_file").(string),
+		HTTPProxy:             data.Get("http_proxy").(string),
+		NoProxy:               data.Get("no_proxy").(string),
+		RequestTimeout:        time.Duration(data.Get("request_timeout").(int)) * time.Second,
+		MaxIdleConns:          data.Get("max_idle_connections").(int),
+		IdleConnTimeout:       time.Duration(data.Get("idle_connection_timeout").(int)) * time.Second,
+		DisableKeepAlives:     data.Get("disable_keep_alives").(bool),
+		DebugHTTP:             data.Get("debug_http").(bool),
+		ReadOnly:              data.Get("read_only").(bool),
+		LogContext:            ctx,
+		Headers:               data.Get("headers").(map[string]interface{}),
+		MaxRetries:            data.Get("max_retries").(int),
+		RetryWaitMin:          time.Duration(data.Get("retry_wait_min").(int)) * time.Second,
+		RetryWaitMax:          time.Duration(data.Get("retry_wait_max").(int)) * time.Second,
+		RequestsPerSecond:     data.Get("requests_per_second").(float64),
+		MaxConcurrentRequests: data.Get("max_concurrent_requests").(int),
+	}
+
+	apiTokenFile := data.Get("api_token_file").(string)
+	apiTokenCommand := data.Get("api_token_command").(string)
+
+	tokenSources := 0
+	for _, value := range []string{config.APIToken, apiTokenFile, apiTokenCommand} {
+		if value != "" {
+			tokenSources++
+		}
+	}
+	if tokenSources > 1 {
+		return nil, diag.Errorf("Only one of api_token, api_token_file and api_token_command may be set, including via their environment variables")
+	}
+
+	switch {
+	case apiTokenFile != "":
+		config.APITokenLoader = apiTokenFromFile(apiTokenFile)
+	case apiTokenCommand != "":
+		config.APITokenLoader = apiTokenFromCommand(apiTokenCommand)
+	}
+	if config.APITokenLoader != nil {
+		apiToken, err := config.APITokenLoader()
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		config.APIToken = apiToken
+	}
+
+	if config.RetryWaitMin > config.RetryWaitMax {
+		return nil, diag.Errorf("retry_wait_min (%v) must not be greater than retry_wait_max (%v)", config.RetryWaitMin, config.RetryWaitMax)
 	}
 
 	netboxClient, clientError := config.Client()
@@ -123,32 +394,65 @@ func providerConfigure(ctx context.Context, data *schema.ResourceData) (interfac
 		return nil, diag.FromErr(clientError)
 	}
 
+	state := newProviderState(netboxClient.(*client.NetBoxAPI))
+
 	// Unless explicitly switched off, use the client to retrieve the Netbox version
 	// so we can determine compatibility of the provider with the used Netbox
 	skipVersionCheck := data.Get("skip_version_check").(bool)
 
 	if !skipVersionCheck {
-		req := status.NewStatusListParams()
-		res, err := netboxClient.(*client.NetBoxAPI).Status.StatusList(req, nil)
+		req := status.NewStatusListParamsWithContext(ctx)
+		res, err := state.Status.StatusList(req, nil)
 
 		if err != nil {
 			return nil, diag.FromErr(err)
 		}
 
-		netboxVersion := res.GetPayload().(map[string]interface{})["netbox-version"].(string)
-
-		supportedVersions := []string{"3.1.11", "3.1.10", "3.1.9", "3.1.8", "3.1.7", "3.1.6", "3.1.5", "3.1.4", "3.1.3", "3.1.2", "3.1.1"}
-
-		if !slices.Contains(supportedVersions, netboxVersion) {
-
-			// Currently, there is no way to test these warnings. There is an issue to track this: https://github.com/hashicorp/terraform-plugin-sdk/issues/864
+		netboxVersion, err := getNetboxVersionFromStatus(res.GetPayload())
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Unable to determine Netbox version",
+				Detail:   fmt.Sprintf("%v. Checks for version dependent features are skipped.", err),
+			})
+		} else if version, err := semver.NewVersion(netboxVersion); err != nil {
 			diags = append(diags, diag.Diagnostic{
 				Severity: diag.Warning,
-				Summary:  "Possibly unsupported Netbox version",
-				Detail:   fmt.Sprintf("Your Netbox version is v%v. The provider was successfully tested against the following versions:\n\n  %v\n\nUnexpected errors may occur.", netboxVersion, strings.Join(supportedVersions, ", ")),
+				Summary:  "Unable to parse Netbox version",
+				Detail:   fmt.Sprintf("Your Netbox version %q is not a semantic version: %v. Checks for version dependent features are skipped.", netboxVersion, err),
 			})
+		} else {
+			state.netboxVersion = version
+
+			if !isSupportedNetboxVersion(version) {
+
+				// Currently, there is no way to test these warnings. There is an issue to track this: https://github.com/hashicorp/terraform-plugin-sdk/issues/864
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Possibly unsupported Netbox version",
+					Detail:   fmt.Sprintf("Your Netbox version is v%v. The provider was successfully tested against the following versions:\n\n  %v\n\nUnexpected errors may occur.", netboxVersion, supportedNetboxVersions),
+				})
+			}
+		}
+	}
+
+	state.autoCreateTags = data.Get("auto_create_tags").(bool)
+	state.ignoreUnmanagedCustomFields = data.Get("ignore_unmanaged_custom_fields").(bool)
+	state.readOnly = config.ReadOnly
+
+	if defaultTenant, ok := data.GetOk("default_tenant"); ok {
+		tenantID, err := getTenantIDFromIDOrSlug(state.NetBoxAPI, defaultTenant.(string))
+		if err != nil {
+			return nil, diag.Errorf("Error resolving default_tenant: %v", err)
+		}
+		state.defaultTenantID = tenantID
+	}
+
+	if defaultTags, ok := data.GetOk("default_tags"); ok {
+		if block, ok := defaultTags.([]interface{})[0].(map[string]interface{}); ok {
+			state.defaultTags = block["tags"].(*schema.Set).List()
 		}
 	}
 
-	return netboxClient, diags
+	return state, diags
 }
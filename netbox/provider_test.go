@@ -3,11 +3,14 @@ package netbox
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -44,6 +47,23 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
+// newTestProviderState returns a provider state talking to a fake Netbox
+// that serves every request with handler.
+func newTestProviderState(t *testing.T, handler http.HandlerFunc) *providerState {
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	config := Config{
+		APIToken:  "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL: ts.URL,
+	}
+	netboxClient, err := config.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newProviderState(netboxClient.(*client.NetBoxAPI))
+}
+
 func testProviderConfig(plattform string) string {
 	return fmt.Sprintf(`
 	resource "netbox_platform" "testplatform" {
@@ -64,7 +84,7 @@ func providerInvalidConfigure() schema.ConfigureContextFunc {
 			return nil, diag.FromErr(clientError)
 		}
 
-		return netboxClient, diags
+		return newProviderState(netboxClient.(*client.NetBoxAPI)), diags
 	}
 }
 
@@ -0,0 +1,122 @@
+package netbox
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimitTransport is a transport that throttles the requests sent to
+// Netbox. It enforces a maximum request rate and a maximum number of requests
+// in flight at the same time. Both limits are shared by every request made
+// through the transport, regardless of the resource issuing it.
+type rateLimitTransport struct {
+	original  http.RoundTripper
+	limiter   *requestRateLimiter
+	semaphore chan struct{}
+}
+
+func newRateLimitTransport(original http.RoundTripper, requestsPerSecond float64, maxConcurrentRequests int) *rateLimitTransport {
+	t := &rateLimitTransport{
+		original: original,
+	}
+	if requestsPerSecond > 0 {
+		t.limiter = newRequestRateLimiter(requestsPerSecond)
+	}
+	if maxConcurrentRequests > 0 {
+		t.semaphore = make(chan struct{}, maxConcurrentRequests)
+	}
+	return t
+}
+
+// RoundTrip waits until the request is allowed by the configured limits and
+// then sends it.
+func (t *rateLimitTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+
+	if t.semaphore != nil {
+		select {
+		case t.semaphore <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if t.limiter != nil {
+		if err := t.limiter.wait(r); err != nil {
+			t.release()
+			return nil, err
+		}
+	}
+
+	resp, err := t.original.RoundTrip(r)
+	if err != nil || t.semaphore == nil {
+		t.release()
+		return resp, err
+	}
+
+	// The request is in flight until its response body has been consumed
+	resp.Body = &releasingReadCloser{ReadCloser: resp.Body, release: t.release}
+	return resp, nil
+}
+
+func (t *rateLimitTransport) release() {
+	if t.semaphore != nil {
+		<-t.semaphore
+	}
+}
+
+// releasingReadCloser calls release exactly once when it is closed.
+type releasingReadCloser struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (r *releasingReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+// requestRateLimiter spaces requests evenly so that no more than the
+// configured number of requests per second are started.
+type requestRateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRequestRateLimiter(requestsPerSecond float64) *requestRateLimiter {
+	return &requestRateLimiter{
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+	}
+}
+
+// wait reserves the next free slot and blocks until it is reached or the
+// request is cancelled.
+func (l *requestRateLimiter) wait(r *http.Request) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	slot := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-r.Context().Done():
+		return r.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
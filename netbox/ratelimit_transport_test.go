@@ -0,0 +1,103 @@
+package netbox
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	netboxClient "github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/status"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitTransportLimitsRequestRate(t *testing.T) {
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := &http.Client{
+		Transport: newRateLimitTransport(http.DefaultTransport, 20, 0),
+	}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		resp, err := client.Get(ts.URL)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// The first request is sent immediately, the remaining four are spaced 50ms apart
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+}
+
+func TestRateLimitTransportLimitsConcurrentRequests(t *testing.T) {
+
+	var inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := &http.Client{
+		Transport: newRateLimitTransport(http.DefaultTransport, 0, 2),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(ts.URL)
+			if assert.NoError(t, err) {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight))
+}
+
+func TestClientWithRateLimit(t *testing.T) {
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"netbox-version": "3.1.11"}`))
+	}))
+	defer ts.Close()
+
+	config := Config{
+		APIToken:              "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL:             ts.URL,
+		RequestsPerSecond:     50,
+		MaxConcurrentRequests: 1,
+	}
+
+	client, err := config.Client()
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		req := status.NewStatusListParams()
+		_, err = client.(*netboxClient.NetBoxAPI).Status.StatusList(req, nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
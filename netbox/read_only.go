@@ -0,0 +1,64 @@
+package netbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// errReadOnlyMode is returned for requests that would modify Netbox while the
+// provider is in read-only mode.
+var errReadOnlyMode = errors.New("the provider is configured with read_only = true")
+
+// readOnlyTransport is a transport that rejects every request that could
+// modify Netbox, i.e. everything but GET, HEAD and OPTIONS requests.
+type readOnlyTransport struct {
+	original http.RoundTripper
+}
+
+// RoundTrip sends the request if it cannot modify Netbox.
+func (t readOnlyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return t.original.RoundTrip(r)
+	}
+
+	if r.Body != nil {
+		r.Body.Close()
+	}
+	return nil, fmt.Errorf("%w, refusing to send %s %s", errReadOnlyMode, r.Method, r.URL.Path)
+}
+
+// readOnlyDiagnostics returns the diagnostics for an operation on a resource
+// that was refused because the provider is in read-only mode.
+func readOnlyDiagnostics(resourceType string, operation string) diag.Diagnostics {
+	return diag.Diagnostics{diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  "Netbox provider is in read-only mode",
+		Detail:   fmt.Sprintf("The provider is configured with read_only = true, so %s cannot %s objects in Netbox. Data sources and refreshing resources still work.", resourceType, operation),
+	}}
+}
+
+// guardReadOnly makes the create, update and delete functions of the resource
+// fail before calling the API if the provider is in read-only mode.
+func guardReadOnly(resourceType string, r *schema.Resource) {
+	r.CreateContext = guardReadOnlyOperation(resourceType, "create", r.CreateContext)
+	r.UpdateContext = guardReadOnlyOperation(resourceType, "update", r.UpdateContext)
+	r.DeleteContext = guardReadOnlyOperation(resourceType, "delete", r.DeleteContext)
+}
+
+func guardReadOnlyOperation(resourceType string, operation string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if m.(*providerState).readOnly {
+			return readOnlyDiagnostics(resourceType, operation)
+		}
+		return f(ctx, d, m)
+	}
+}
@@ -0,0 +1,68 @@
+package netbox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	netboxClient "github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/client/status"
+	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyTransportRejectsWrites(t *testing.T) {
+
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"netbox-version": "3.1.11"}`))
+	}))
+	defer ts.Close()
+
+	config := Config{
+		APIToken:  "07b12b765127747e4afd56cb531b7bf9c61f3c30",
+		ServerURL: ts.URL,
+		ReadOnly:  true,
+	}
+
+	client, err := config.Client()
+	assert.NoError(t, err)
+	api := client.(*netboxClient.NetBoxAPI)
+
+	_, err = api.Status.StatusList(status.NewStatusListParams(), nil)
+	assert.NoError(t, err)
+
+	name, slug := "foo", "foo"
+	params := dcim.NewDcimSitesCreateParams().WithData(&models.WritableSite{Name: &name, Slug: &slug})
+	_, err = api.Dcim.DcimSitesCreate(params, nil)
+	assert.ErrorIs(t, err, errReadOnlyMode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	diags := diagFromNetboxError(err, resourceNetboxSite().Schema)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "Netbox provider is in read-only mode", diags[0].Summary)
+}
+
+func TestGuardReadOnly(t *testing.T) {
+
+	r := Provider().ResourcesMap["netbox_site"]
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"name": "foo"})
+
+	for _, f := range []func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics{
+		r.CreateContext,
+		r.UpdateContext,
+		r.DeleteContext,
+	} {
+		diags := f(context.Background(), d, &providerState{readOnly: true})
+		assert.Len(t, diags, 1)
+		assert.Equal(t, "Netbox provider is in read-only mode", diags[0].Summary)
+		assert.Contains(t, diags[0].Detail, "netbox_site")
+	}
+}
@@ -1,21 +1,24 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/ipam"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxAggregate() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxAggregateCreate,
-		Read:   resourceNetboxAggregateRead,
-		Update: resourceNetboxAggregateUpdate,
-		Delete: resourceNetboxAggregateDelete,
+		CreateContext: resourceNetboxAggregateCreate,
+		ReadContext:   resourceNetboxAggregateRead,
+		UpdateContext: resourceNetboxAggregateUpdate,
+		DeleteContext: resourceNetboxAggregateDelete,
+		CustomizeDiff: customdiff.All(customizeDiffTagsAll, customizeDiffDefaultTenant, customizeDiffCustomFieldsAll),
 
 		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/ipam/#aggregates):
 
@@ -34,6 +37,7 @@ func resourceNetboxAggregate() *schema.Resource {
 			"tenant_id": {
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 			},
 			"rir_id": {
 				Type:     schema.TypeInt,
@@ -47,14 +51,17 @@ func resourceNetboxAggregate() *schema.Resource {
 				Optional: true,
 				Set:      schema.HashString,
 			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
 	}
 }
-func resourceNetboxAggregateCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxAggregateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	data := models.WritableAggregate{}
 
 	prefix := d.Get("prefix").(string)
@@ -71,32 +78,41 @@ func resourceNetboxAggregateCreate(d *schema.ResourceData, m interface{}) error
 		data.Rir = int64ToPtr(int64(rirID.(int)))
 	}
 
-	data.Tags, _ = getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
+	data.Tags = tags
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
 
-	params := ipam.NewIpamAggregatesCreateParams().WithData(&data)
+	params := ipam.NewIpamAggregatesCreateParamsWithContext(ctx).WithData(&data)
 	res, err := api.Ipam.IpamAggregatesCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxAggregate().Schema)
 	}
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxAggregateRead(d, m)
+	return resourceNetboxAggregateRead(ctx, d, m)
 }
 
-func resourceNetboxAggregateRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxAggregateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := ipam.NewIpamAggregatesReadParams().WithID(id)
+	params := ipam.NewIpamAggregatesReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Ipam.IpamAggregatesRead(params, nil)
 	if err != nil {
-		errorcode := err.(*ipam.IpamAggregatesReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxAggregate().Schema)
 	}
 
 	d.Set("description", res.GetPayload().Description)
@@ -116,13 +132,16 @@ func resourceNetboxAggregateRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("rir_id", nil)
 	}
 
-	d.Set("tags", getTagListFromNestedTagList(res.GetPayload().Tags))
+	setTagsFromNestedTagList(api, d, res.GetPayload().Tags)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
 
-func resourceNetboxAggregateUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxAggregateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.WritableAggregate{}
 	prefix := d.Get("prefix").(string)
@@ -139,23 +158,33 @@ func resourceNetboxAggregateUpdate(d *schema.ResourceData, m interface{}) error
 		data.Rir = int64ToPtr(int64(rirID.(int)))
 	}
 
-	data.Tags, _ = getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
+	data.Tags = tags
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
 
-	params := ipam.NewIpamAggregatesUpdateParams().WithID(id).WithData(&data)
+	params := ipam.NewIpamAggregatesUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 	_, err := api.Ipam.IpamAggregatesUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxAggregate().Schema)
 	}
-	return resourceNetboxAggregateRead(d, m)
+	return resourceNetboxAggregateRead(ctx, d, m)
 }
 
-func resourceNetboxAggregateDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxAggregateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := ipam.NewIpamAggregatesDeleteParams().WithID(id)
+	params := ipam.NewIpamAggregatesDeleteParamsWithContext(ctx).WithID(id)
 	_, err := api.Ipam.IpamAggregatesDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxAggregate().Schema)
 	}
 	d.SetId("")
 	return nil
@@ -1,20 +1,24 @@
 package netbox
 
 import (
-	"github.com/fbreckle/go-netbox/netbox/client"
+	"context"
+	"strconv"
+
 	"github.com/fbreckle/go-netbox/netbox/client/ipam"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"strconv"
 )
 
 func resourceNetboxAvailableIPAddress() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxAvailableIPAddressCreate,
-		Read:   resourceNetboxAvailableIPAddressRead,
-		Update: resourceNetboxAvailableIPAddressUpdate,
-		Delete: resourceNetboxAvailableIPAddressDelete,
+		CreateContext: resourceNetboxAvailableIPAddressCreate,
+		ReadContext:   resourceNetboxAvailableIPAddressRead,
+		UpdateContext: resourceNetboxAvailableIPAddressUpdate,
+		DeleteContext: resourceNetboxAvailableIPAddressDelete,
+		CustomizeDiff: customdiff.All(customizeDiffTagsAll, customizeDiffDefaultTenant, customizeDiffCustomFieldsAll),
 
 		Schema: map[string]*schema.Schema{
 			"prefix_id": &schema.Schema{
@@ -41,6 +45,7 @@ func resourceNetboxAvailableIPAddress() *schema.Resource {
 			"tenant_id": &schema.Schema{
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 			},
 			"status": &schema.Schema{
 				Type:         schema.TypeString,
@@ -64,6 +69,9 @@ func resourceNetboxAvailableIPAddress() *schema.Resource {
 				Optional: true,
 				Set:      schema.HashString,
 			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -71,8 +79,8 @@ func resourceNetboxAvailableIPAddress() *schema.Resource {
 	}
 }
 
-func resourceNetboxAvailableIPAddressCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxAvailableIPAddressCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	prefixId := int64(d.Get("prefix_id").(int))
 	vrfId := int64(int64(d.Get("vrf_id").(int)))
 	rangeId := int64(d.Get("ip_range_id").(int))
@@ -82,38 +90,47 @@ func resourceNetboxAvailableIPAddressCreate(d *schema.ResourceData, m interface{
 	data := models.AvailableIP{
 		Vrf: &nestedvrf,
 	}
+	var payload []*models.IPAddress
 	if prefixId != 0 {
-		params := ipam.NewIpamPrefixesAvailableIpsCreateParams().WithID(prefixId).WithData([]*models.AvailableIP{&data})
-		res, _ := api.Ipam.IpamPrefixesAvailableIpsCreate(params, nil)
-		// Since we generated the ip_address set that now
-		d.SetId(strconv.FormatInt(res.Payload[0].ID, 10))
-		d.Set("ip_address", *res.Payload[0].Address)
+		params := ipam.NewIpamPrefixesAvailableIpsCreateParamsWithContext(ctx).WithID(prefixId).WithData([]*models.AvailableIP{&data})
+		res, err := api.Ipam.IpamPrefixesAvailableIpsCreate(params, nil)
+		if err != nil {
+			return diagFromNetboxError(err, resourceNetboxAvailableIPAddress().Schema)
+		}
+		payload = res.GetPayload()
 	}
 	if rangeId != 0 {
-		params := ipam.NewIpamIPRangesAvailableIpsCreateParams().WithID(rangeId).WithData([]*models.AvailableIP{&data})
-		res, _ := api.Ipam.IpamIPRangesAvailableIpsCreate(params, nil)
-		// Since we generated the ip_address set that now
-		d.SetId(strconv.FormatInt(res.Payload[0].ID, 10))
-		d.Set("ip_address", *res.Payload[0].Address)
+		params := ipam.NewIpamIPRangesAvailableIpsCreateParamsWithContext(ctx).WithID(rangeId).WithData([]*models.AvailableIP{&data})
+		res, err := api.Ipam.IpamIPRangesAvailableIpsCreate(params, nil)
+		if err != nil {
+			return diagFromNetboxError(err, resourceNetboxAvailableIPAddress().Schema)
+		}
+		payload = res.GetPayload()
 	}
-	return resourceNetboxAvailableIPAddressUpdate(d, m)
+	if len(payload) == 0 {
+		return diag.Errorf("Netbox returned no available IP address, the prefix or IP range is probably full")
+	}
+
+	// Since we generated the ip_address set that now
+	d.SetId(strconv.FormatInt(payload[0].ID, 10))
+	d.Set("ip_address", payload[0].Address)
+	return resourceNetboxAvailableIPAddressUpdate(ctx, d, m)
 }
 
-func resourceNetboxAvailableIPAddressRead(d *schema.ResourceData, m interface{}) error {
+func resourceNetboxAvailableIPAddressRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	api := m.(*client.NetBoxAPI)
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := ipam.NewIpamIPAddressesReadParams().WithID(id)
+	params := ipam.NewIpamIPAddressesReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Ipam.IpamIPAddressesRead(params, nil)
 	if err != nil {
-		errorcode := err.(*ipam.IpamIPAddressesReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxAvailableIPAddress().Schema)
 	}
 
 	if res.GetPayload().AssignedObjectID != nil {
@@ -141,13 +158,16 @@ func resourceNetboxAvailableIPAddressRead(d *schema.ResourceData, m interface{})
 	d.Set("ip_address", res.GetPayload().Address)
 	d.Set("description", res.GetPayload().Description)
 	d.Set("status", res.GetPayload().Status.Value)
-	d.Set("tags", getTagListFromNestedTagList(res.GetPayload().Tags))
+	setTagsFromNestedTagList(api, d, res.GetPayload().Tags)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
 	return nil
 }
 
-func resourceNetboxAvailableIPAddressUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceNetboxAvailableIPAddressUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	api := m.(*client.NetBoxAPI)
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.WritableIPAddress{}
@@ -183,26 +203,36 @@ func resourceNetboxAvailableIPAddressUpdate(d *schema.ResourceData, m interface{
 		data.Tenant = int64ToPtr(int64(tenantID.(int)))
 	}
 
-	data.Tags, _ = getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
+	data.Tags = tags
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
 
-	params := ipam.NewIpamIPAddressesUpdateParams().WithID(id).WithData(&data)
+	params := ipam.NewIpamIPAddressesUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Ipam.IpamIPAddressesUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxAvailableIPAddress().Schema)
 	}
-	return resourceNetboxAvailableIPAddressRead(d, m)
+	return resourceNetboxAvailableIPAddressRead(ctx, d, m)
 }
 
-func resourceNetboxAvailableIPAddressDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxAvailableIPAddressDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := ipam.NewIpamIPAddressesDeleteParams().WithID(id)
+	params := ipam.NewIpamIPAddressesDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Ipam.IpamIPAddressesDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxAvailableIPAddress().Schema)
 	}
 	return nil
 }
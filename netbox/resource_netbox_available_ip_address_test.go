@@ -1,8 +1,10 @@
 package netbox
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"regexp"
 	"testing"
 
@@ -10,6 +12,8 @@ import (
 	"github.com/fbreckle/go-netbox/netbox/client/ipam"
 	"github.com/fbreckle/go-netbox/netbox/models"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccNetboxAvailableIPAddress_basic(t *testing.T) {
@@ -223,6 +227,46 @@ resource "netbox_available_ip_address" "test_range3" {
 	})
 }
 
+func TestResourceNetboxAvailableIPAddressCreateFullPrefix(t *testing.T) {
+
+	api := newTestProviderState(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/ipam/prefixes/5/available-ips/", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"detail": "An insufficient number of IP addresses are available within the prefix 10.0.0.0/30 (1 requested, 0 available)"}`))
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxAvailableIPAddress().Schema, map[string]interface{}{
+		"prefix_id": 5,
+	})
+
+	diags := resourceNetboxAvailableIPAddressCreate(context.Background(), d, api)
+	assert.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Detail, "insufficient number of IP addresses")
+	assert.Equal(t, "", d.Id())
+}
+
+func TestResourceNetboxAvailableIPAddressCreateEmptyResponse(t *testing.T) {
+
+	api := newTestProviderState(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/ipam/ip-ranges/6/available-ips/", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`[]`))
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxAvailableIPAddress().Schema, map[string]interface{}{
+		"ip_range_id": 6,
+	})
+
+	diags := resourceNetboxAvailableIPAddressCreate(context.Background(), d, api)
+	assert.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, "no available IP address")
+	assert.Equal(t, "", d.Id())
+}
+
 func init() {
 	resource.AddTestSweepers("netbox_available_ip_address", &resource.Sweeper{
 		Name:         "netbox_available_ip_address",
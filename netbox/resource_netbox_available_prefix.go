@@ -6,19 +6,21 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/ipam"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxAvailablePrefix() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxAvailablePrefixCreate,
-		Read:   resourceNetboxPrefixRead,
-		Update: resourceNetboxPrefixUpdate,
-		Delete: resourceNetboxPrefixDelete,
+		CreateContext: resourceNetboxAvailablePrefixCreate,
+		ReadContext:   resourceNetboxPrefixRead,
+		UpdateContext: resourceNetboxPrefixUpdate,
+		DeleteContext: resourceNetboxPrefixDelete,
+		CustomizeDiff: customdiff.All(customizeDiffTagsAll, customizeDiffDefaultTenant, customizeDiffCustomFieldsAll),
 
 		Schema: map[string]*schema.Schema{
 			"parent_prefix_id": {
@@ -60,6 +62,7 @@ func resourceNetboxAvailablePrefix() *schema.Resource {
 			"tenant_id": {
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 			},
 			"site_id": {
 				Type:     schema.TypeInt,
@@ -81,6 +84,9 @@ func resourceNetboxAvailablePrefix() *schema.Resource {
 				Optional: true,
 				Set:      schema.HashString,
 			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: func(c context.Context, rd *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -118,24 +124,24 @@ func resourceNetboxAvailablePrefixParseImport(import_str string) (int, string, i
 	return parent_id, parts[1], prefix_length, nil
 }
 
-func resourceNetboxAvailablePrefixCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxAvailablePrefixCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	parent_prefix_id := int64(d.Get("parent_prefix_id").(int))
 	prefix_length := int64(d.Get("prefix_length").(int))
 	data := models.PrefixLength{
 		PrefixLength: &prefix_length,
 	}
-	params := ipam.NewIpamPrefixesAvailablePrefixesCreateParams().WithID(parent_prefix_id).WithData(&data)
+	params := ipam.NewIpamPrefixesAvailablePrefixesCreateParamsWithContext(ctx).WithID(parent_prefix_id).WithData(&data)
 
 	res, err := api.Ipam.IpamPrefixesAvailablePrefixesCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxAvailablePrefix().Schema)
 	}
 
 	payload := res.GetPayload()
 	d.SetId(strconv.FormatInt(payload.ID, 10))
 	d.Set("prefix", payload.Prefix)
 
-	return resourceNetboxPrefixUpdate(d, m)
+	return resourceNetboxPrefixUpdate(ctx, d, m)
 }
@@ -58,6 +58,7 @@ resource "netbox_available_prefix" "test" {
 					resource.TestCheckResourceAttr(resourceName, "description", testDesc),
 					resource.TestCheckResourceAttr(resourceName, "tags.#", "1"),
 					resource.TestCheckResourceAttr(resourceName, "tags.0", testName),
+					resource.TestCheckResourceAttr(resourceName, "tags_all.#", "1"),
 					resource.TestCheckResourceAttr(resourceName, "mark_utilized", "true"),
 				),
 			},
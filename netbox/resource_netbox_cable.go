@@ -0,0 +1,352 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/fbreckle/go-netbox/netbox/client/circuits"
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// cableTerminationTypes are the object types that a cable can be attached to.
+var cableTerminationTypes = []string{
+	"dcim.interface",
+	"dcim.frontport",
+	"dcim.rearport",
+	"dcim.consoleport",
+	"dcim.consoleserverport",
+	"dcim.powerport",
+	"dcim.poweroutlet",
+	"dcim.powerfeed",
+	"circuits.circuittermination",
+}
+
+func resourceNetboxCable() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNetboxCableCreate,
+		ReadContext:   resourceNetboxCableRead,
+		UpdateContext: resourceNetboxCableUpdate,
+		DeleteContext: resourceNetboxCableDelete,
+		CustomizeDiff: customdiff.All(customizeDiffTagsAll, customizeDiffCustomFieldsAll),
+
+		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/models/dcim/cable/):
+
+> All connections between device components in NetBox are represented using cables. A cable represents a direct physical connection between two termination points, such as between a console port and a patch panel port, or between two network interfaces.
+
+Both terminations must be free when the cable is created.`,
+
+		Schema: map[string]*schema.Schema{
+			"termination_a_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(cableTerminationTypes, false),
+				Description:  "The object type of the A side, e.g. `dcim.interface`, `dcim.frontport`, `dcim.consoleport`, `dcim.powerport` or `circuits.circuittermination`.",
+			},
+			"termination_a_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the object on the A side.",
+			},
+			"termination_b_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(cableTerminationTypes, false),
+				Description:  "The object type of the B side.",
+			},
+			"termination_b_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the object on the B side.",
+			},
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"cat3", "cat5", "cat5e", "cat6", "cat6a", "cat7", "cat7a", "cat8",
+					"dac-active", "dac-passive", "mrj21-trunk", "coaxial",
+					"mmf", "mmf-om1", "mmf-om2", "mmf-om3", "mmf-om4", "mmf-om5",
+					"smf", "smf-os1", "smf-os2", "aoc", "power",
+				}, false),
+			},
+			"status": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "connected",
+				ValidateFunc: validation.StringInSlice([]string{"connected", "planned", "decommissioning"}, false),
+			},
+			"label": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"color_hex": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile("^[0-9a-f]{6}$"),
+					"Must be a lowercase hex color like 00ff00"),
+			},
+			"length": &schema.Schema{
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				RequiredWith: []string{"length_unit"},
+			},
+			"length_unit": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"length"},
+				ValidateFunc: validation.StringInSlice([]string{"km", "m", "cm", "mi", "ft", "in"}, false),
+			},
+			"tags": &schema.Schema{
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				Set:      schema.HashString,
+			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+	}
+}
+
+// getWritableCableFromResourceData returns the cable to create or update, and
+// the fields that the model omits if they are empty.
+func getWritableCableFromResourceData(api *providerState, d *schema.ResourceData) (*models.WritableCable, map[string]interface{}, diag.Diagnostics) {
+	terminationAType := d.Get("termination_a_type").(string)
+	terminationAID := int64(d.Get("termination_a_id").(int))
+	terminationBType := d.Get("termination_b_type").(string)
+	terminationBID := int64(d.Get("termination_b_id").(int))
+
+	data := models.WritableCable{
+		TerminationaType: &terminationAType,
+		TerminationaID:   &terminationAID,
+		TerminationbType: &terminationBType,
+		TerminationbID:   &terminationBID,
+		Status:           d.Get("status").(string),
+	}
+
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	data.Tags = tags
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	data.CustomFields = customFields
+
+	// The model omits empty values, so they are sent as fields to reset them
+	// on update
+	fields := map[string]interface{}{
+		"type":        d.Get("type").(string),
+		"label":       d.Get("label").(string),
+		"color":       d.Get("color_hex").(string),
+		"length":      nil,
+		"length_unit": d.Get("length_unit").(string),
+	}
+
+	if length, ok := d.GetOk("length"); ok {
+		fields["length"] = length.(float64)
+	}
+
+	return &data, fields, nil
+}
+
+// getCableTerminationCable returns the cable attached to the termination of
+// the given type and ID, or nil if the termination is free, along with the
+// display name of the termination.
+func getCableTerminationCable(ctx context.Context, api *providerState, terminationType string, id int64) (*models.NestedCable, string, error) {
+	switch terminationType {
+	case "dcim.interface":
+		res, err := api.Dcim.DcimInterfacesRead(dcim.NewDcimInterfacesReadParamsWithContext(ctx).WithID(id), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetPayload().Cable, res.GetPayload().Display, nil
+	case "dcim.frontport":
+		res, err := api.Dcim.DcimFrontPortsRead(dcim.NewDcimFrontPortsReadParamsWithContext(ctx).WithID(id), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetPayload().Cable, res.GetPayload().Display, nil
+	case "dcim.rearport":
+		res, err := api.Dcim.DcimRearPortsRead(dcim.NewDcimRearPortsReadParamsWithContext(ctx).WithID(id), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetPayload().Cable, res.GetPayload().Display, nil
+	case "dcim.consoleport":
+		res, err := api.Dcim.DcimConsolePortsRead(dcim.NewDcimConsolePortsReadParamsWithContext(ctx).WithID(id), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetPayload().Cable, res.GetPayload().Display, nil
+	case "dcim.consoleserverport":
+		res, err := api.Dcim.DcimConsoleServerPortsRead(dcim.NewDcimConsoleServerPortsReadParamsWithContext(ctx).WithID(id), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetPayload().Cable, res.GetPayload().Display, nil
+	case "dcim.powerport":
+		res, err := api.Dcim.DcimPowerPortsRead(dcim.NewDcimPowerPortsReadParamsWithContext(ctx).WithID(id), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetPayload().Cable, res.GetPayload().Display, nil
+	case "dcim.poweroutlet":
+		res, err := api.Dcim.DcimPowerOutletsRead(dcim.NewDcimPowerOutletsReadParamsWithContext(ctx).WithID(id), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetPayload().Cable, res.GetPayload().Display, nil
+	case "dcim.powerfeed":
+		res, err := api.Dcim.DcimPowerFeedsRead(dcim.NewDcimPowerFeedsReadParamsWithContext(ctx).WithID(id), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetPayload().Cable, res.GetPayload().Display, nil
+	case "circuits.circuittermination":
+		res, err := api.Circuits.CircuitsCircuitTerminationsRead(circuits.NewCircuitsCircuitTerminationsReadParamsWithContext(ctx).WithID(id), nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.GetPayload().Cable, res.GetPayload().Display, nil
+	}
+	return nil, "", fmt.Errorf("unsupported cable termination type %q", terminationType)
+}
+
+func resourceNetboxCableCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	// Check both ends up front to name the occupied termination and the cable
+	// attached to it, which the error of Netbox does not
+	for _, side := range []string{"a", "b"} {
+		terminationType := d.Get("termination_" + side + "_type").(string)
+		terminationID := int64(d.Get("termination_" + side + "_id").(int))
+
+		cable, display, err := getCableTerminationCable(ctx, api, terminationType, terminationID)
+		if err != nil {
+			return diagFromNetboxError(err, resourceNetboxCable().Schema)
+		}
+		if cable != nil {
+			return diag.Diagnostics{diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Cable termination is already connected",
+				Detail:        fmt.Sprintf("%s %d (%s) already has cable %d attached. Delete that cable or choose another termination.", terminationType, terminationID, display, cable.ID),
+				AttributePath: cty.GetAttrPath("termination_" + side + "_id"),
+			}}
+		}
+	}
+
+	data, fields, diags := getWritableCableFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	params := dcim.NewDcimCablesCreateParamsWithContext(ctx).WithData(data)
+
+	res, err := api.Dcim.DcimCablesCreate(params, nil, withRequestFields(fields))
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxCable().Schema)
+	}
+
+	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
+
+	return resourceNetboxCableRead(ctx, d, m)
+}
+
+func resourceNetboxCableRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimCablesReadParamsWithContext(ctx).WithID(id)
+
+	res, err := api.Dcim.DcimCablesRead(params, nil)
+	if err != nil {
+		if isNotFoundError(err) {
+			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
+			d.SetId("")
+			return nil
+		}
+		return diagFromNetboxError(err, resourceNetboxCable().Schema)
+	}
+
+	cable := res.GetPayload()
+
+	d.Set("termination_a_type", cable.TerminationaType)
+	d.Set("termination_a_id", cable.TerminationaID)
+	d.Set("termination_b_type", cable.TerminationbType)
+	d.Set("termination_b_id", cable.TerminationbID)
+	d.Set("type", cable.Type)
+	d.Set("label", cable.Label)
+	d.Set("color_hex", cable.Color)
+	d.Set("length", cable.Length)
+
+	if cable.Status != nil {
+		d.Set("status", cable.Status.Value)
+	}
+
+	if cable.LengthUnit != nil {
+		d.Set("length_unit", cable.LengthUnit.Value)
+	} else {
+		d.Set("length_unit", nil)
+	}
+
+	setTagsFromNestedTagList(api, d, cable.Tags)
+	if err := setCustomFieldsFromAPI(api, d, cable.CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetboxCableUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+
+	data, fields, diags := getWritableCableFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	params := dcim.NewDcimCablesPartialUpdateParamsWithContext(ctx).WithID(id).WithData(data)
+
+	_, err := api.Dcim.DcimCablesPartialUpdate(params, nil, withRequestFields(fields))
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxCable().Schema)
+	}
+
+	return resourceNetboxCableRead(ctx, d, m)
+}
+
+func resourceNetboxCableDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimCablesDeleteParamsWithContext(ctx).WithID(id)
+
+	_, err := api.Dcim.DcimCablesDelete(params, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxCable().Schema)
+	}
+	return nil
+}
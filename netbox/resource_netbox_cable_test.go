@@ -0,0 +1,201 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAccNetboxCableFullDependencies(testName string) string {
+	return testAccNetboxDeviceFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_device" "test_a" {
+  name = "%[1]s_a"
+  role_id = netbox_device_role.test.id
+  device_type_id = netbox_device_type.test.id
+  site_id = netbox_site.test.id
+}
+
+resource "netbox_device" "test_b" {
+  name = "%[1]s_b"
+  role_id = netbox_device_role.test.id
+  device_type_id = netbox_device_type.test.id
+  site_id = netbox_site.test.id
+}
+
+resource "netbox_device_interface" "test_a" {
+  name = "eth0"
+  device_id = netbox_device.test_a.id
+  type = "1000base-t"
+}
+
+resource "netbox_device_interface" "test_b" {
+  name = "eth0"
+  device_id = netbox_device.test_b.id
+  type = "1000base-t"
+}
+
+resource "netbox_device_interface" "test_c" {
+  name = "eth1"
+  device_id = netbox_device.test_b.id
+  type = "1000base-t"
+}`, testName)
+}
+
+func TestAccNetboxCable_basic(t *testing.T) {
+
+	testSlug := "cable_basic"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNetboxCableFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_cable" "test" {
+  termination_a_type = "dcim.interface"
+  termination_a_id = netbox_device_interface.test_a.id
+  termination_b_type = "dcim.interface"
+  termination_b_id = netbox_device_interface.test_b.id
+  type = "cat6"
+  status = "planned"
+  label = "%s"
+  color_hex = "00ff00"
+  length = 2.5
+  length_unit = "m"
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_cable.test", "termination_a_type", "dcim.interface"),
+					resource.TestCheckResourceAttrPair("netbox_cable.test", "termination_a_id", "netbox_device_interface.test_a", "id"),
+					resource.TestCheckResourceAttr("netbox_cable.test", "termination_b_type", "dcim.interface"),
+					resource.TestCheckResourceAttrPair("netbox_cable.test", "termination_b_id", "netbox_device_interface.test_b", "id"),
+					resource.TestCheckResourceAttr("netbox_cable.test", "type", "cat6"),
+					resource.TestCheckResourceAttr("netbox_cable.test", "status", "planned"),
+					resource.TestCheckResourceAttr("netbox_cable.test", "label", testName),
+					resource.TestCheckResourceAttr("netbox_cable.test", "color_hex", "00ff00"),
+					resource.TestCheckResourceAttr("netbox_cable.test", "length", "2.5"),
+					resource.TestCheckResourceAttr("netbox_cable.test", "length_unit", "m"),
+				),
+			},
+			{
+				Config: testAccNetboxCableFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_cable" "test" {
+  termination_a_type = "dcim.interface"
+  termination_a_id = netbox_device_interface.test_a.id
+  termination_b_type = "dcim.interface"
+  termination_b_id = netbox_device_interface.test_b.id
+  label = "%s"
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_cable.test", "type", ""),
+					resource.TestCheckResourceAttr("netbox_cable.test", "status", "connected"),
+					resource.TestCheckResourceAttr("netbox_cable.test", "color_hex", ""),
+					resource.TestCheckResourceAttr("netbox_cable.test", "length", "0"),
+					resource.TestCheckResourceAttr("netbox_cable.test", "length_unit", ""),
+				),
+			},
+			{
+				Config: testAccNetboxCableFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_cable" "test" {
+  termination_a_type = "dcim.interface"
+  termination_a_id = netbox_device_interface.test_a.id
+  termination_b_type = "dcim.interface"
+  termination_b_id = netbox_device_interface.test_b.id
+  label = "%[1]s"
+}
+
+resource "netbox_cable" "occupied" {
+  termination_a_type = "dcim.interface"
+  termination_a_id = netbox_device_interface.test_a.id
+  termination_b_type = "dcim.interface"
+  termination_b_id = netbox_device_interface.test_c.id
+  label = "%[1]s_occupied"
+
+  depends_on = [netbox_cable.test]
+}`, testName),
+				ExpectError: regexp.MustCompile("Cable termination is already connected"),
+			},
+			{
+				ResourceName:      "netbox_cable.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceNetboxCableCreateRejectsConnectedTermination(t *testing.T) {
+
+	var methods []string
+	api := newTestProviderState(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/dcim/interfaces/4/":
+			w.Write([]byte(`{"id": 4, "display": "eth0", "name": "eth0"}`))
+		case "/api/dcim/front-ports/5/":
+			w.Write([]byte(`{"id": 5, "display": "Port 1", "name": "Port 1", "cable": {"id": 12, "label": ""}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail": "Not found."}`))
+		}
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxCable().Schema, map[string]interface{}{
+		"termination_a_type": "dcim.interface",
+		"termination_a_id":   4,
+		"termination_b_type": "dcim.frontport",
+		"termination_b_id":   5,
+	})
+
+	diags := resourceNetboxCableCreate(context.Background(), d, api)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "Cable termination is already connected", diags[0].Summary)
+	assert.Contains(t, diags[0].Detail, "dcim.frontport 5 (Port 1) already has cable 12 attached")
+	assert.Equal(t, cty.GetAttrPath("termination_b_id"), diags[0].AttributePath)
+	assert.Equal(t, []string{
+		"GET /api/dcim/interfaces/4/",
+		"GET /api/dcim/front-ports/5/",
+	}, methods)
+}
+
+func init() {
+	resource.AddTestSweepers("netbox_cable", &resource.Sweeper{
+		Name:         "netbox_cable",
+		Dependencies: []string{},
+		F: func(region string) error {
+			m, err := sharedClientForRegion(region)
+			if err != nil {
+				return fmt.Errorf("Error getting client: %s", err)
+			}
+			api := m.(*client.NetBoxAPI)
+			params := dcim.NewDcimCablesListParams()
+			res, err := api.Dcim.DcimCablesList(params, nil)
+			if err != nil {
+				return err
+			}
+			for _, cable := range res.GetPayload().Results {
+				if strings.HasPrefix(cable.Label, testPrefix) {
+					deleteParams := dcim.NewDcimCablesDeleteParams().WithID(cable.ID)
+					_, err := api.Dcim.DcimCablesDelete(deleteParams, nil)
+					if err != nil && !isNotFoundError(err) {
+						return err
+					}
+					log.Print("[DEBUG] Deleted a cable")
+				}
+			}
+			return nil
+		},
+	})
+}
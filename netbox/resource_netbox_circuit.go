@@ -1,21 +1,24 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/circuits"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxCircuit() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxCircuitCreate,
-		Read:   resourceNetboxCircuitRead,
-		Update: resourceNetboxCircuitUpdate,
-		Delete: resourceNetboxCircuitDelete,
+		CreateContext: resourceNetboxCircuitCreate,
+		ReadContext:   resourceNetboxCircuitRead,
+		UpdateContext: resourceNetboxCircuitUpdate,
+		DeleteContext: resourceNetboxCircuitDelete,
+		CustomizeDiff: customdiff.All(customizeDiffDefaultTenant, customizeDiffCustomFieldsAll),
 
 		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/circuits/#circuits_1):
 
@@ -39,12 +42,15 @@ func resourceNetboxCircuit() *schema.Resource {
 			"tenant_id": &schema.Schema{
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 			},
 			"status": &schema.Schema{
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validation.StringInSlice([]string{"planned", "provisioning", "active", "offline", "deprovisioning", "decommissioning"}, false),
 			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -52,8 +58,8 @@ func resourceNetboxCircuit() *schema.Resource {
 	}
 }
 
-func resourceNetboxCircuitCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	data := models.WritableCircuit{}
 
@@ -79,33 +85,38 @@ func resourceNetboxCircuitCreate(d *schema.ResourceData, m interface{}) error {
 
 	data.Tags = []*models.NestedTag{}
 
-	params := circuits.NewCircuitsCircuitsCreateParams().WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := circuits.NewCircuitsCircuitsCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Circuits.CircuitsCircuitsCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuit().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxCircuitRead(d, m)
+	return resourceNetboxCircuitRead(ctx, d, m)
 }
 
-func resourceNetboxCircuitRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := circuits.NewCircuitsCircuitsReadParams().WithID(id)
+	params := circuits.NewCircuitsCircuitsReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Circuits.CircuitsCircuitsRead(params, nil)
 
 	if err != nil {
-		errorcode := err.(*circuits.CircuitsCircuitsReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuit().Schema)
 	}
 
 	d.Set("cid", res.GetPayload().Cid)
@@ -129,11 +140,15 @@ func resourceNetboxCircuitRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("tenant_id", nil)
 	}
 
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
-func resourceNetboxCircuitUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.WritableCircuit{}
@@ -160,25 +175,31 @@ func resourceNetboxCircuitUpdate(d *schema.ResourceData, m interface{}) error {
 
 	data.Tags = []*models.NestedTag{}
 
-	params := circuits.NewCircuitsCircuitsPartialUpdateParams().WithID(id).WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := circuits.NewCircuitsCircuitsPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Circuits.CircuitsCircuitsPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuit().Schema)
 	}
 
-	return resourceNetboxCircuitRead(d, m)
+	return resourceNetboxCircuitRead(ctx, d, m)
 }
 
-func resourceNetboxCircuitDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := circuits.NewCircuitsCircuitsDeleteParams().WithID(id)
+	params := circuits.NewCircuitsCircuitsDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Circuits.CircuitsCircuitsDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuit().Schema)
 	}
 	return nil
 }
@@ -1,21 +1,23 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/circuits"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxCircuitProvider() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxCircuitProviderCreate,
-		Read:   resourceNetboxCircuitProviderRead,
-		Update: resourceNetboxCircuitProviderUpdate,
-		Delete: resourceNetboxCircuitProviderDelete,
+		CreateContext: resourceNetboxCircuitProviderCreate,
+		ReadContext:   resourceNetboxCircuitProviderRead,
+		UpdateContext: resourceNetboxCircuitProviderUpdate,
+		DeleteContext: resourceNetboxCircuitProviderDelete,
+		CustomizeDiff: customizeDiffCustomFieldsAll,
 
 		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/circuits/#providers):
 
@@ -34,6 +36,8 @@ func resourceNetboxCircuitProvider() *schema.Resource {
 				Computed:     true,
 				ValidateFunc: validation.StringLenBetween(0, 30),
 			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -41,8 +45,8 @@ func resourceNetboxCircuitProvider() *schema.Resource {
 	}
 }
 
-func resourceNetboxCircuitProviderCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitProviderCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	data := models.Provider{}
 
@@ -59,43 +63,52 @@ func resourceNetboxCircuitProviderCreate(d *schema.ResourceData, m interface{})
 
 	data.Tags = []*models.NestedTag{}
 
-	params := circuits.NewCircuitsProvidersCreateParams().WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := circuits.NewCircuitsProvidersCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Circuits.CircuitsProvidersCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitProvider().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxCircuitProviderRead(d, m)
+	return resourceNetboxCircuitProviderRead(ctx, d, m)
 }
 
-func resourceNetboxCircuitProviderRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitProviderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := circuits.NewCircuitsProvidersReadParams().WithID(id)
+	params := circuits.NewCircuitsProvidersReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Circuits.CircuitsProvidersRead(params, nil)
 
 	if err != nil {
-		errorcode := err.(*circuits.CircuitsProvidersReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitProvider().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
 	d.Set("slug", res.GetPayload().Slug)
 
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
-func resourceNetboxCircuitProviderUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitProviderUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.Provider{}
@@ -113,25 +126,31 @@ func resourceNetboxCircuitProviderUpdate(d *schema.ResourceData, m interface{})
 
 	data.Tags = []*models.NestedTag{}
 
-	params := circuits.NewCircuitsProvidersPartialUpdateParams().WithID(id).WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := circuits.NewCircuitsProvidersPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Circuits.CircuitsProvidersPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitProvider().Schema)
 	}
 
-	return resourceNetboxCircuitProviderRead(d, m)
+	return resourceNetboxCircuitProviderRead(ctx, d, m)
 }
 
-func resourceNetboxCircuitProviderDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitProviderDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := circuits.NewCircuitsProvidersDeleteParams().WithID(id)
+	params := circuits.NewCircuitsProvidersDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Circuits.CircuitsProvidersDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitProvider().Schema)
 	}
 	return nil
 }
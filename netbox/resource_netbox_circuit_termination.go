@@ -1,21 +1,22 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/circuits"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxCircuitTermination() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxCircuitTerminationCreate,
-		Read:   resourceNetboxCircuitTerminationRead,
-		Update: resourceNetboxCircuitTerminationUpdate,
-		Delete: resourceNetboxCircuitTerminationDelete,
+		CreateContext: resourceNetboxCircuitTerminationCreate,
+		ReadContext:   resourceNetboxCircuitTerminationRead,
+		UpdateContext: resourceNetboxCircuitTerminationUpdate,
+		DeleteContext: resourceNetboxCircuitTerminationDelete,
 
 		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/circuits/#circuit-terminations):
 
@@ -52,8 +53,8 @@ func resourceNetboxCircuitTermination() *schema.Resource {
 	}
 }
 
-func resourceNetboxCircuitTerminationCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitTerminationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	data := models.WritableCircuitTermination{}
 
@@ -80,33 +81,32 @@ func resourceNetboxCircuitTerminationCreate(d *schema.ResourceData, m interface{
 		data.UpstreamSpeed = int64ToPtr(int64(upstreamspeedValue.(int)))
 	}
 
-	params := circuits.NewCircuitsCircuitTerminationsCreateParams().WithData(&data)
+	params := circuits.NewCircuitsCircuitTerminationsCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Circuits.CircuitsCircuitTerminationsCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitTermination().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxCircuitTerminationRead(d, m)
+	return resourceNetboxCircuitTerminationRead(ctx, d, m)
 }
 
-func resourceNetboxCircuitTerminationRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitTerminationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := circuits.NewCircuitsCircuitTerminationsReadParams().WithID(id)
+	params := circuits.NewCircuitsCircuitTerminationsReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Circuits.CircuitsCircuitTerminationsRead(params, nil)
 
 	if err != nil {
-		errorcode := err.(*circuits.CircuitsCircuitTerminationsReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitTermination().Schema)
 	}
 
 	d.Set("term_side", res.GetPayload().TermSide)
@@ -138,8 +138,8 @@ func resourceNetboxCircuitTerminationRead(d *schema.ResourceData, m interface{})
 	return nil
 }
 
-func resourceNetboxCircuitTerminationUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitTerminationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.WritableCircuitTermination{}
@@ -166,25 +166,25 @@ func resourceNetboxCircuitTerminationUpdate(d *schema.ResourceData, m interface{
 	if ok {
 		data.UpstreamSpeed = int64ToPtr(int64(upstreamspeedValue.(int)))
 	}
-	params := circuits.NewCircuitsCircuitTerminationsPartialUpdateParams().WithID(id).WithData(&data)
+	params := circuits.NewCircuitsCircuitTerminationsPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Circuits.CircuitsCircuitTerminationsPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitTermination().Schema)
 	}
 
-	return resourceNetboxCircuitTerminationRead(d, m)
+	return resourceNetboxCircuitTerminationRead(ctx, d, m)
 }
 
-func resourceNetboxCircuitTerminationDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitTerminationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := circuits.NewCircuitsCircuitTerminationsDeleteParams().WithID(id)
+	params := circuits.NewCircuitsCircuitTerminationsDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Circuits.CircuitsCircuitTerminationsDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitTermination().Schema)
 	}
 	return nil
 }
@@ -1,21 +1,23 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/circuits"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxCircuitType() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxCircuitTypeCreate,
-		Read:   resourceNetboxCircuitTypeRead,
-		Update: resourceNetboxCircuitTypeUpdate,
-		Delete: resourceNetboxCircuitTypeDelete,
+		CreateContext: resourceNetboxCircuitTypeCreate,
+		ReadContext:   resourceNetboxCircuitTypeRead,
+		UpdateContext: resourceNetboxCircuitTypeUpdate,
+		DeleteContext: resourceNetboxCircuitTypeDelete,
+		CustomizeDiff: customizeDiffCustomFieldsAll,
 
 		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/circuits/#circuit-types):
 
@@ -32,6 +34,8 @@ func resourceNetboxCircuitType() *schema.Resource {
 				Computed:     true,
 				ValidateFunc: validation.StringLenBetween(0, 30),
 			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -39,8 +43,8 @@ func resourceNetboxCircuitType() *schema.Resource {
 	}
 }
 
-func resourceNetboxCircuitTypeCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitTypeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	data := models.CircuitType{}
 
@@ -55,43 +59,52 @@ func resourceNetboxCircuitTypeCreate(d *schema.ResourceData, m interface{}) erro
 		data.Slug = strToPtr(slugValue.(string))
 	}
 
-	params := circuits.NewCircuitsCircuitTypesCreateParams().WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := circuits.NewCircuitsCircuitTypesCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Circuits.CircuitsCircuitTypesCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitType().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxCircuitTypeRead(d, m)
+	return resourceNetboxCircuitTypeRead(ctx, d, m)
 }
 
-func resourceNetboxCircuitTypeRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitTypeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := circuits.NewCircuitsCircuitTypesReadParams().WithID(id)
+	params := circuits.NewCircuitsCircuitTypesReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Circuits.CircuitsCircuitTypesRead(params, nil)
 
 	if err != nil {
-		errorcode := err.(*circuits.CircuitsCircuitTypesReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitType().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
 	d.Set("slug", res.GetPayload().Slug)
 
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
-func resourceNetboxCircuitTypeUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitTypeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.CircuitType{}
@@ -107,25 +120,31 @@ func resourceNetboxCircuitTypeUpdate(d *schema.ResourceData, m interface{}) erro
 		data.Slug = strToPtr(slugValue.(string))
 	}
 
-	params := circuits.NewCircuitsCircuitTypesPartialUpdateParams().WithID(id).WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := circuits.NewCircuitsCircuitTypesPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Circuits.CircuitsCircuitTypesPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitType().Schema)
 	}
 
-	return resourceNetboxCircuitTypeRead(d, m)
+	return resourceNetboxCircuitTypeRead(ctx, d, m)
 }
 
-func resourceNetboxCircuitTypeDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCircuitTypeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := circuits.NewCircuitsCircuitTypesDeleteParams().WithID(id)
+	params := circuits.NewCircuitsCircuitTypesDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Circuits.CircuitsCircuitTypesDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCircuitType().Schema)
 	}
 	return nil
 }
@@ -1,20 +1,23 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/virtualization"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceNetboxCluster() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxClusterCreate,
-		Read:   resourceNetboxClusterRead,
-		Update: resourceNetboxClusterUpdate,
-		Delete: resourceNetboxClusterDelete,
+		CreateContext: resourceNetboxClusterCreate,
+		ReadContext:   resourceNetboxClusterRead,
+		UpdateContext: resourceNetboxClusterUpdate,
+		DeleteContext: resourceNetboxClusterDelete,
+		CustomizeDiff: customdiff.All(customizeDiffTagsAll, customizeDiffCustomFieldsAll),
 
 		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/virtualization/#clusters):
 
@@ -47,6 +50,9 @@ func resourceNetboxCluster() *schema.Resource {
 				Optional: true,
 				Set:      schema.HashString,
 			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -54,8 +60,8 @@ func resourceNetboxCluster() *schema.Resource {
 	}
 }
 
-func resourceNetboxClusterCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxClusterCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	data := models.WritableCluster{}
 
@@ -75,36 +81,44 @@ func resourceNetboxClusterCreate(d *schema.ResourceData, m interface{}) error {
 		data.Site = &siteID
 	}
 
-	tags, _ := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
 	data.Tags = tags
 
-	params := virtualization.NewVirtualizationClustersCreateParams().WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := virtualization.NewVirtualizationClustersCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Virtualization.VirtualizationClustersCreate(params, nil)
 	if err != nil {
 		//return errors.New(getTextFromError(err))
-		return err
+		return diagFromNetboxError(err, resourceNetboxCluster().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxClusterRead(d, m)
+	return resourceNetboxClusterRead(ctx, d, m)
 }
 
-func resourceNetboxClusterRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := virtualization.NewVirtualizationClustersReadParams().WithID(id)
+	params := virtualization.NewVirtualizationClustersReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Virtualization.VirtualizationClustersRead(params, nil)
 	if err != nil {
-		errorcode := err.(*virtualization.VirtualizationClustersReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxCluster().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
@@ -122,12 +136,15 @@ func resourceNetboxClusterRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("site_id", nil)
 	}
 
-	d.Set("tags", getTagListFromNestedTagList(res.GetPayload().Tags))
+	setTagsFromNestedTagList(api, d, res.GetPayload().Tags)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
 	return nil
 }
 
-func resourceNetboxClusterUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxClusterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.WritableCluster{}
@@ -148,28 +165,37 @@ func resourceNetboxClusterUpdate(d *schema.ResourceData, m interface{}) error {
 		data.Site = &siteID
 	}
 
-	tags, _ := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
 	data.Tags = tags
 
-	params := virtualization.NewVirtualizationClustersPartialUpdateParams().WithID(id).WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := virtualization.NewVirtualizationClustersPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Virtualization.VirtualizationClustersPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCluster().Schema)
 	}
 
-	return resourceNetboxClusterRead(d, m)
+	return resourceNetboxClusterRead(ctx, d, m)
 }
 
-func resourceNetboxClusterDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxClusterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := virtualization.NewVirtualizationClustersDeleteParams().WithID(id)
+	params := virtualization.NewVirtualizationClustersDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Virtualization.VirtualizationClustersDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxCluster().Schema)
 	}
 	return nil
 }
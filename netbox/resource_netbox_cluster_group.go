@@ -1,21 +1,23 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/virtualization"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxClusterGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxClusterGroupCreate,
-		Read:   resourceNetboxClusterGroupRead,
-		Update: resourceNetboxClusterGroupUpdate,
-		Delete: resourceNetboxClusterGroupDelete,
+		CreateContext: resourceNetboxClusterGroupCreate,
+		ReadContext:   resourceNetboxClusterGroupRead,
+		UpdateContext: resourceNetboxClusterGroupUpdate,
+		DeleteContext: resourceNetboxClusterGroupDelete,
+		CustomizeDiff: customizeDiffCustomFieldsAll,
 
 		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/virtualization/#cluster-groups):
 
@@ -36,6 +38,8 @@ func resourceNetboxClusterGroup() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -43,8 +47,8 @@ func resourceNetboxClusterGroup() *schema.Resource {
 	}
 }
 
-func resourceNetboxClusterGroupCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxClusterGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	data := models.ClusterGroup{}
 
@@ -65,42 +69,51 @@ func resourceNetboxClusterGroupCreate(d *schema.ResourceData, m interface{}) err
 		data.Description = description.(string)
 	}
 
-	params := virtualization.NewVirtualizationClusterGroupsCreateParams().WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := virtualization.NewVirtualizationClusterGroupsCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Virtualization.VirtualizationClusterGroupsCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxClusterGroup().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxClusterGroupRead(d, m)
+	return resourceNetboxClusterGroupRead(ctx, d, m)
 }
 
-func resourceNetboxClusterGroupRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxClusterGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := virtualization.NewVirtualizationClusterGroupsReadParams().WithID(id)
+	params := virtualization.NewVirtualizationClusterGroupsReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Virtualization.VirtualizationClusterGroupsRead(params, nil)
 	if err != nil {
-		errorcode := err.(*virtualization.VirtualizationClusterGroupsReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxClusterGroup().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
 	d.Set("slug", res.GetPayload().Slug)
 	d.Set("description", res.GetPayload().Description)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
-func resourceNetboxClusterGroupUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxClusterGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.ClusterGroup{}
@@ -127,25 +140,31 @@ func resourceNetboxClusterGroupUpdate(d *schema.ResourceData, m interface{}) err
 		}
 	}
 
-	params := virtualization.NewVirtualizationClusterGroupsPartialUpdateParams().WithID(id).WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := virtualization.NewVirtualizationClusterGroupsPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Virtualization.VirtualizationClusterGroupsPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxClusterGroup().Schema)
 	}
 
-	return resourceNetboxClusterGroupRead(d, m)
+	return resourceNetboxClusterGroupRead(ctx, d, m)
 }
 
-func resourceNetboxClusterGroupDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxClusterGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := virtualization.NewVirtualizationClusterGroupsDeleteParams().WithID(id)
+	params := virtualization.NewVirtualizationClusterGroupsDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Virtualization.VirtualizationClusterGroupsDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxClusterGroup().Schema)
 	}
 	return nil
 }
@@ -1,22 +1,22 @@
 package netbox
 
 import (
-	"fmt"
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/extras"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceCustomField() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxCustomFieldCreate,
-		Read:   resourceNetboxCustomFieldRead,
-		Update: resourceNetboxCustomFieldUpdate,
-		Delete: resourceNetboxCustomFieldDelete,
+		CreateContext: resourceNetboxCustomFieldCreate,
+		ReadContext:   resourceNetboxCustomFieldRead,
+		UpdateContext: resourceNetboxCustomFieldUpdate,
+		DeleteContext: resourceNetboxCustomFieldDelete,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -94,8 +94,8 @@ func resourceCustomField() *schema.Resource {
 	}
 }
 
-func resourceNetboxCustomFieldUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCustomFieldUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 
@@ -112,7 +112,7 @@ func resourceNetboxCustomFieldUpdate(d *schema.ResourceData, m interface{}) erro
 	choices, ok := d.GetOk("choices")
 	if ok {
 		if data.Type != "select" && data.Type != "multiselect" {
-			return fmt.Errorf("choices may be set only for custom selection fields")
+			return diag.Errorf("choices may be set only for custom selection fields")
 		}
 		for _, choice := range choices.(*schema.Set).List() {
 			data.Choices = append(data.Choices, choice.(string))
@@ -135,19 +135,21 @@ func resourceNetboxCustomFieldUpdate(d *schema.ResourceData, m interface{}) erro
 		data.ValidationMinimum = int64ToPtr(int64(vmin.(int)))
 	}
 
-	params := extras.NewExtrasCustomFieldsUpdateParams().WithID(id).WithData(data)
+	params := extras.NewExtrasCustomFieldsUpdateParamsWithContext(ctx).WithID(id).WithData(data)
 	res, err := api.Extras.ExtrasCustomFieldsUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceCustomField().Schema)
 	}
 
+	api.customFieldCache.invalidate()
+
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxCustomFieldRead(d, m)
+	return resourceNetboxCustomFieldRead(ctx, d, m)
 }
 
-func resourceNetboxCustomFieldCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCustomFieldCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	data := &models.WritableCustomField{
 		Name:            strToPtr(d.Get("name").(string)),
@@ -162,7 +164,7 @@ func resourceNetboxCustomFieldCreate(d *schema.ResourceData, m interface{}) erro
 	choices, ok := d.GetOk("choices")
 	if ok {
 		if data.Type != "select" && data.Type != "multiselect" {
-			return fmt.Errorf("choices may be set only for custom selection fields")
+			return diag.Errorf("choices may be set only for custom selection fields")
 		}
 		for _, choice := range choices.(*schema.Set).List() {
 			data.Choices = append(data.Choices, choice.(string))
@@ -185,35 +187,32 @@ func resourceNetboxCustomFieldCreate(d *schema.ResourceData, m interface{}) erro
 		data.ValidationMinimum = int64ToPtr(int64(vmin.(int)))
 	}
 
-	params := extras.NewExtrasCustomFieldsCreateParams().WithData(data)
+	params := extras.NewExtrasCustomFieldsCreateParamsWithContext(ctx).WithData(data)
 
 	res, err := api.Extras.ExtrasCustomFieldsCreate(params, nil)
 	if err != nil {
 		//return errors.New(getTextFromError(err))
-		return err
+		return diagFromNetboxError(err, resourceCustomField().Schema)
 	}
 
+	api.customFieldCache.invalidate()
+
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxCustomFieldRead(d, m)
+	return resourceNetboxCustomFieldRead(ctx, d, m)
 }
 
-func resourceNetboxCustomFieldRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCustomFieldRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := extras.NewExtrasCustomFieldsReadParams().WithID(id)
+	params := extras.NewExtrasCustomFieldsReadParamsWithContext(ctx).WithID(id)
 	res, err := api.Extras.ExtrasCustomFieldsRead(params, nil)
 	if err != nil {
-		errapi, ok := err.(*extras.ExtrasCustomFieldsReadDefault)
-		if !ok {
-			return err
-		}
-		errorcode := errapi.Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceCustomField().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
@@ -242,10 +241,16 @@ func resourceNetboxCustomFieldRead(d *schema.ResourceData, m interface{}) error
 	return nil
 }
 
-func resourceNetboxCustomFieldDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxCustomFieldDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := extras.NewExtrasCustomFieldsDeleteParams().WithID(id)
+	params := extras.NewExtrasCustomFieldsDeleteParamsWithContext(ctx).WithID(id)
 	_, err := api.Extras.ExtrasCustomFieldsDelete(params, nil)
-	return err
+	if err != nil {
+		return diagFromNetboxError(err, resourceCustomField().Schema)
+	}
+
+	api.customFieldCache.invalidate()
+
+	return nil
 }
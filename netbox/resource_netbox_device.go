@@ -4,11 +4,12 @@ import (
 	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/dcim"
 	"github.com/fbreckle/go-netbox/netbox/models"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxDevice() *schema.Resource {
@@ -17,6 +18,7 @@ func resourceNetboxDevice() *schema.Resource {
 		ReadContext:   resourceNetboxDeviceRead,
 		UpdateContext: resourceNetboxDeviceUpdate,
 		DeleteContext: resourceNetboxDeviceDelete,
+		CustomizeDiff: customdiff.All(customizeDiffTagsAll, customizeDiffDefaultTenant, customizeDiffCustomFieldsAll),
 
 		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/devices/#devices):
 
@@ -34,6 +36,7 @@ func resourceNetboxDevice() *schema.Resource {
 			"tenant_id": &schema.Schema{
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 			},
 			"role_id": &schema.Schema{
 				Type:     schema.TypeInt,
@@ -47,6 +50,27 @@ func resourceNetboxDevice() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
+			"location_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"rack_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"position": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"rack_id", "face"},
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The lowest-numbered rack unit occupied by the device.",
+			},
+			"face": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"rack_id"},
+				ValidateFunc: validation.StringInSlice([]string{"front", "rear"}, false),
+			},
 			"comments": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -59,6 +83,9 @@ func resourceNetboxDevice() *schema.Resource {
 				Optional: true,
 				Set:      schema.HashString,
 			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 			"primary_ipv4": &schema.Schema{
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -71,7 +98,7 @@ func resourceNetboxDevice() *schema.Resource {
 }
 
 func resourceNetboxDeviceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	api := m.(*client.NetBoxAPI)
+	api := m.(*providerState)
 
 	name := d.Get("name").(string)
 
@@ -109,13 +136,43 @@ func resourceNetboxDeviceCreate(ctx context.Context, d *schema.ResourceData, m i
 		data.Site = &siteID
 	}
 
-	data.Tags, _ = getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	locationIDValue, ok := d.GetOk("location_id")
+	if ok {
+		locationID := int64(locationIDValue.(int))
+		data.Location = &locationID
+	}
+
+	rackIDValue, ok := d.GetOk("rack_id")
+	if ok {
+		rackID := int64(rackIDValue.(int))
+		data.Rack = &rackID
+	}
+
+	positionValue, ok := d.GetOk("position")
+	if ok {
+		position := int64(positionValue.(int))
+		data.Position = &position
+	}
 
-	params := dcim.NewDcimDevicesCreateParams().WithData(&data)
+	data.Face = d.Get("face").(string)
+
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
+	data.Tags = tags
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := dcim.NewDcimDevicesCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Dcim.DcimDevicesCreate(params, nil)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromNetboxError(err, resourceNetboxDevice().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
@@ -124,23 +181,22 @@ func resourceNetboxDeviceCreate(ctx context.Context, d *schema.ResourceData, m i
 }
 
 func resourceNetboxDeviceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	api := m.(*client.NetBoxAPI)
+	api := m.(*providerState)
 
 	var diags diag.Diagnostics
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 
-	params := dcim.NewDcimDevicesReadParams().WithID(id)
+	params := dcim.NewDcimDevicesReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Dcim.DcimDevicesRead(params, nil)
 	if err != nil {
-		errorcode := err.(*dcim.DcimDevicesReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return diag.FromErr(err)
+		return diagFromNetboxError(err, resourceNetboxDevice().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
@@ -173,16 +229,39 @@ func resourceNetboxDeviceRead(ctx context.Context, d *schema.ResourceData, m int
 		d.Set("site_id", nil)
 	}
 
+	if res.GetPayload().Location != nil {
+		d.Set("location_id", res.GetPayload().Location.ID)
+	} else {
+		d.Set("location_id", nil)
+	}
+
+	if res.GetPayload().Rack != nil {
+		d.Set("rack_id", res.GetPayload().Rack.ID)
+	} else {
+		d.Set("rack_id", nil)
+	}
+
+	d.Set("position", res.GetPayload().Position)
+
+	if res.GetPayload().Face != nil {
+		d.Set("face", res.GetPayload().Face.Value)
+	} else {
+		d.Set("face", nil)
+	}
+
 	d.Set("comments", res.GetPayload().Comments)
 
 	d.Set("serial", res.GetPayload().Serial)
 
-	d.Set("tags", getTagListFromNestedTagList(res.GetPayload().Tags))
+	setTagsFromNestedTagList(api, d, res.GetPayload().Tags)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
 	return diags
 }
 
 func resourceNetboxDeviceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	api := m.(*client.NetBoxAPI)
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.WritableDeviceWithConfigContext{}
@@ -214,6 +293,30 @@ func resourceNetboxDeviceUpdate(ctx context.Context, d *schema.ResourceData, m i
 		data.Site = &siteID
 	}
 
+	// location, rack and position are sent as null and face as empty if unset
+	// to remove the device from them
+	fields := map[string]interface{}{
+		"location": nil,
+		"rack":     nil,
+		"position": nil,
+		"face":     d.Get("face").(string),
+	}
+
+	locationIDValue, ok := d.GetOk("location_id")
+	if ok {
+		fields["location"] = int64(locationIDValue.(int))
+	}
+
+	rackIDValue, ok := d.GetOk("rack_id")
+	if ok {
+		fields["rack"] = int64(rackIDValue.(int))
+	}
+
+	positionValue, ok := d.GetOk("position")
+	if ok {
+		fields["position"] = int64(positionValue.(int))
+	}
+
 	commentsValue, ok := d.GetOk("comments")
 	if ok {
 		comments := commentsValue.(string)
@@ -229,7 +332,17 @@ func resourceNetboxDeviceUpdate(ctx context.Context, d *schema.ResourceData, m i
 		data.PrimaryIp4 = &primaryIP
 	}
 
-	data.Tags, _ = getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
+	data.Tags = tags
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
 
 	if d.HasChanges("comments") {
 		// check if comment is set
@@ -257,27 +370,27 @@ func resourceNetboxDeviceUpdate(ctx context.Context, d *schema.ResourceData, m i
 		data.Serial = serial
 	}
 
-	params := dcim.NewDcimDevicesUpdateParams().WithID(id).WithData(&data)
+	params := dcim.NewDcimDevicesUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
-	_, err := api.Dcim.DcimDevicesUpdate(params, nil)
+	_, err := api.Dcim.DcimDevicesUpdate(params, nil, withRequestFields(fields))
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromNetboxError(err, resourceNetboxDevice().Schema)
 	}
 
 	return resourceNetboxDeviceRead(ctx, d, m)
 }
 
 func resourceNetboxDeviceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	api := m.(*client.NetBoxAPI)
+	api := m.(*providerState)
 
 	var diags diag.Diagnostics
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := dcim.NewDcimDevicesDeleteParams().WithID(id)
+	params := dcim.NewDcimDevicesDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Dcim.DcimDevicesDelete(params, nil)
 	if err != nil {
-		return diag.FromErr(err)
+		return diagFromNetboxError(err, resourceNetboxDevice().Schema)
 	}
 	return diags
 }
@@ -0,0 +1,364 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// deviceInterfaceFields are the fields of a device interface that the models
+// of the go-netbox client lack.
+type deviceInterfaceFields struct {
+	Bridge *models.NestedInterface `json:"bridge"`
+}
+
+func resourceNetboxDeviceInterface() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNetboxDeviceInterfaceCreate,
+		ReadContext:   resourceNetboxDeviceInterfaceRead,
+		UpdateContext: resourceNetboxDeviceInterfaceUpdate,
+		DeleteContext: resourceNetboxDeviceInterfaceDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffTagsAll,
+			customizeDiffCustomFieldsAll,
+			customizeDiffDeviceInterfaceMode,
+			requireNetboxCapability(capabilityInterfaceBridge, "bridge_id"),
+		),
+
+		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/device-types/#interfaces):
+
+> Interfaces in NetBox represent network interfaces used to exchange data with connected devices. On modern networks, these are most commonly Ethernet, but other types are supported as well. IP addresses and VLANs can be assigned to interfaces.
+
+If the interface already exists on the device, e.g. because it was created from the component templates of the device type, it is adopted instead of created.`,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"device_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"type": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The physical type of the interface, e.g. `1000base-t`, `10gbase-x-sfpp`, `lag` or `virtual`.",
+			},
+			"label": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"mtu": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 65536),
+			},
+			"mac_address": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile("^([A-Z0-9]{2}:){5}[A-Z0-9]{2}$"),
+					"Must be like AA:AA:AA:AA:AA"),
+			},
+			"mgmt_only": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"lag_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The ID of the LAG interface this interface is a member of.",
+			},
+			"bridge_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The ID of the interface this interface is bridged with. Requires Netbox 3.2.0 or later.",
+			},
+			"mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"access", "tagged", "tagged-all"}, false),
+				Description:  "The 802.1Q mode of the interface.",
+			},
+			"untagged_vlan_id": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"mode"},
+			},
+			"tagged_vlans": &schema.Schema{
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+				Optional:    true,
+				Description: "The IDs of the tagged VLANs. Requires `mode` to be `tagged`.",
+			},
+			"tags": &schema.Schema{
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				Set:      schema.HashString,
+			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
+		},
+		Importer: naturalKeyImporter("netbox_device_interface", "device/interface-name", lookupDeviceInterfacesByName),
+	}
+}
+
+// customizeDiffDeviceInterfaceMode rejects tagged VLANs on interfaces that are
+// not in tagged mode at plan time, which Netbox would only reject on apply.
+func customizeDiffDeviceInterfaceMode(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	taggedVlans, ok := d.GetOk("tagged_vlans")
+	if !ok || taggedVlans.(*schema.Set).Len() == 0 {
+		return nil
+	}
+	if mode := d.Get("mode").(string); mode != "tagged" && d.NewValueKnown("mode") {
+		return fmt.Errorf("tagged_vlans can only be set if mode is \"tagged\", got %q", mode)
+	}
+	return nil
+}
+
+// getWritableDeviceInterfaceFromResourceData returns the interface to create
+// or update, and the fields that the model lacks or omits if they are empty.
+func getWritableDeviceInterfaceFromResourceData(api *providerState, d *schema.ResourceData) (*models.WritableInterface, map[string]interface{}, diag.Diagnostics) {
+	name := d.Get("name").(string)
+	deviceID := int64(d.Get("device_id").(int))
+	interfaceType := d.Get("type").(string)
+
+	data := models.WritableInterface{
+		Name:        &name,
+		Device:      &deviceID,
+		Type:        &interfaceType,
+		TaggedVlans: []int64{},
+	}
+
+	for _, vlanID := range d.Get("tagged_vlans").(*schema.Set).List() {
+		data.TaggedVlans = append(data.TaggedVlans, int64(vlanID.(int)))
+	}
+
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	data.Tags = tags
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	data.CustomFields = customFields
+
+	// The model omits false, empty and unset values, so they are sent as
+	// fields to reset them on update and when adopting an existing interface
+	fields := map[string]interface{}{
+		"label":         d.Get("label").(string),
+		"description":   d.Get("description").(string),
+		"enabled":       d.Get("enabled").(bool),
+		"mgmt_only":     d.Get("mgmt_only").(bool),
+		"mode":          d.Get("mode").(string),
+		"mtu":           nil,
+		"mac_address":   nil,
+		"lag":           nil,
+		"untagged_vlan": nil,
+	}
+
+	if mtu, ok := d.GetOk("mtu"); ok {
+		fields["mtu"] = int64(mtu.(int))
+	}
+
+	if macAddress, ok := d.GetOk("mac_address"); ok {
+		fields["mac_address"] = macAddress.(string)
+	}
+
+	if lagID, ok := d.GetOk("lag_id"); ok {
+		fields["lag"] = int64(lagID.(int))
+	}
+
+	// Older versions of Netbox ignore bridge, so it is only sent to those
+	// that know it
+	if hasNetboxCapability(api.netboxVersion, capabilityInterfaceBridge) == nil {
+		fields["bridge"] = nil
+		if bridgeID, ok := d.GetOk("bridge_id"); ok {
+			fields["bridge"] = int64(bridgeID.(int))
+		}
+	}
+
+	if untaggedVlanID, ok := d.GetOk("untagged_vlan_id"); ok {
+		fields["untagged_vlan"] = int64(untaggedVlanID.(int))
+	}
+
+	return &data, fields, nil
+}
+
+func resourceNetboxDeviceInterfaceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	// Interfaces created from the component templates of the device type
+	// already exist and are adopted
+	name := d.Get("name").(string)
+	deviceID := strconv.Itoa(d.Get("device_id").(int))
+	listParams := dcim.NewDcimInterfacesListParamsWithContext(ctx)
+	listParams.DeviceID = &deviceID
+	listParams.Name = &name
+
+	existing, err := api.Dcim.DcimInterfacesList(listParams, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxDeviceInterface().Schema)
+	}
+	if len(existing.GetPayload().Results) == 1 {
+		id := existing.GetPayload().Results[0].ID
+		tflog.Debug(ctx, "Adopting existing interface", map[string]interface{}{
+			"name":      name,
+			"id":        id,
+			"device_id": deviceID,
+		})
+		d.SetId(strconv.FormatInt(id, 10))
+		return resourceNetboxDeviceInterfaceUpdate(ctx, d, m)
+	}
+
+	data, fields, diags := getWritableDeviceInterfaceFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	params := dcim.NewDcimInterfacesCreateParamsWithContext(ctx).WithData(data)
+
+	res, err := api.Dcim.DcimInterfacesCreate(params, nil, withRequestFields(fields))
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxDeviceInterface().Schema)
+	}
+
+	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
+
+	return resourceNetboxDeviceInterfaceRead(ctx, d, m)
+}
+
+func resourceNetboxDeviceInterfaceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimInterfacesReadParamsWithContext(ctx).WithID(id)
+
+	var fields deviceInterfaceFields
+	res, err := api.Dcim.DcimInterfacesRead(params, nil, withResponseFields(&fields))
+	if err != nil {
+		if isNotFoundError(err) {
+			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
+			d.SetId("")
+			return nil
+		}
+		return diagFromNetboxError(err, resourceNetboxDeviceInterface().Schema)
+	}
+
+	iface := res.GetPayload()
+
+	d.Set("name", iface.Name)
+	d.Set("label", iface.Label)
+	d.Set("description", iface.Description)
+	d.Set("enabled", iface.Enabled)
+	d.Set("mgmt_only", iface.MgmtOnly)
+	d.Set("mtu", iface.Mtu)
+	d.Set("mac_address", iface.MacAddress)
+
+	if iface.Device != nil {
+		d.Set("device_id", iface.Device.ID)
+	}
+
+	if iface.Type != nil {
+		d.Set("type", iface.Type.Value)
+	}
+
+	if iface.Mode != nil {
+		d.Set("mode", iface.Mode.Value)
+	} else {
+		d.Set("mode", nil)
+	}
+
+	if iface.Lag != nil {
+		d.Set("lag_id", iface.Lag.ID)
+	} else {
+		d.Set("lag_id", nil)
+	}
+
+	if fields.Bridge != nil {
+		d.Set("bridge_id", fields.Bridge.ID)
+	} else {
+		d.Set("bridge_id", nil)
+	}
+
+	if iface.UntaggedVlan != nil {
+		d.Set("untagged_vlan_id", iface.UntaggedVlan.ID)
+	} else {
+		d.Set("untagged_vlan_id", nil)
+	}
+
+	var taggedVlans []int64
+	for _, vlan := range iface.TaggedVlans {
+		taggedVlans = append(taggedVlans, vlan.ID)
+	}
+	d.Set("tagged_vlans", taggedVlans)
+
+	setTagsFromNestedTagList(api, d, iface.Tags)
+	if err := setCustomFieldsFromAPI(api, d, iface.CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetboxDeviceInterfaceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+
+	data, fields, diags := getWritableDeviceInterfaceFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	params := dcim.NewDcimInterfacesPartialUpdateParamsWithContext(ctx).WithID(id).WithData(data)
+
+	_, err := api.Dcim.DcimInterfacesPartialUpdate(params, nil, withRequestFields(fields))
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxDeviceInterface().Schema)
+	}
+
+	return resourceNetboxDeviceInterfaceRead(ctx, d, m)
+}
+
+func resourceNetboxDeviceInterfaceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimInterfacesDeleteParamsWithContext(ctx).WithID(id)
+
+	_, err := api.Dcim.DcimInterfacesDelete(params, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxDeviceInterface().Schema)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAccNetboxDeviceInterfaceFullDependencies(testName string) string {
+	return testAccNetboxDeviceFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_device" "test" {
+  name = "%[1]s"
+  role_id = netbox_device_role.test.id
+  device_type_id = netbox_device_type.test.id
+  site_id = netbox_site.test.id
+}
+
+resource "netbox_vlan" "test_1" {
+  name = "%[1]s_1"
+  vid = 1001
+  tags = []
+}
+
+resource "netbox_vlan" "test_2" {
+  name = "%[1]s_2"
+  vid = 1002
+  tags = []
+}`, testName)
+}
+
+func TestAccNetboxDeviceInterface_basic(t *testing.T) {
+
+	testSlug := "dev_iface_basic"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNetboxDeviceInterfaceFullDependencies(testName) + `
+resource "netbox_device_interface" "lag" {
+  name = "Port-Channel1"
+  device_id = netbox_device.test.id
+  type = "lag"
+}
+
+resource "netbox_device_interface" "test" {
+  name = "Ethernet1/1"
+  device_id = netbox_device.test.id
+  type = "1000base-t"
+  description = "uplink"
+  enabled = false
+  mtu = 9000
+  mac_address = "00:1A:2B:3C:4D:5E"
+  mgmt_only = true
+  lag_id = netbox_device_interface.lag.id
+  mode = "tagged"
+  untagged_vlan_id = netbox_vlan.test_1.id
+  tagged_vlans = [netbox_vlan.test_2.id]
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "name", "Ethernet1/1"),
+					resource.TestCheckResourceAttrPair("netbox_device_interface.test", "device_id", "netbox_device.test", "id"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "type", "1000base-t"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "description", "uplink"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "enabled", "false"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "mtu", "9000"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "mac_address", "00:1A:2B:3C:4D:5E"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "mgmt_only", "true"),
+					resource.TestCheckResourceAttrPair("netbox_device_interface.test", "lag_id", "netbox_device_interface.lag", "id"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "mode", "tagged"),
+					resource.TestCheckResourceAttrPair("netbox_device_interface.test", "untagged_vlan_id", "netbox_vlan.test_1", "id"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "tagged_vlans.#", "1"),
+				),
+			},
+			{
+				Config: testAccNetboxDeviceInterfaceFullDependencies(testName) + `
+resource "netbox_device_interface" "lag" {
+  name = "Port-Channel1"
+  device_id = netbox_device.test.id
+  type = "lag"
+}
+
+resource "netbox_device_interface" "test" {
+  name = "Ethernet1/1"
+  device_id = netbox_device.test.id
+  type = "1000base-t"
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "description", ""),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "mtu", "0"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "mac_address", ""),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "mgmt_only", "false"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "lag_id", "0"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "mode", ""),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "untagged_vlan_id", "0"),
+					resource.TestCheckResourceAttr("netbox_device_interface.test", "tagged_vlans.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "netbox_device_interface.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "netbox_device_interface.test",
+				ImportState:       true,
+				ImportStateId:     testName + "/Ethernet1/1",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceNetboxDeviceInterfaceCreateAdoptsExisting(t *testing.T) {
+
+	var methods []string
+	api := newTestProviderState(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+
+		iface := map[string]interface{}{
+			"id":      7,
+			"name":    "eth0",
+			"device":  map[string]interface{}{"id": 3, "name": "sw01"},
+			"type":    map[string]interface{}{"value": "1000base-t", "label": "1000BASE-T"},
+			"enabled": true,
+			"tags":    []interface{}{},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet && r.URL.Path == "/api/dcim/interfaces/" {
+			assert.Equal(t, "3", r.URL.Query().Get("device_id"))
+			assert.Equal(t, "eth0", r.URL.Query().Get("name"))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"count":   1,
+				"results": []interface{}{iface},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(iface)
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxDeviceInterface().Schema, map[string]interface{}{
+		"name":      "eth0",
+		"device_id": 3,
+		"type":      "1000base-t",
+	})
+
+	diags := resourceNetboxDeviceInterfaceCreate(context.Background(), d, api)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, "7", d.Id())
+	assert.Equal(t, []string{
+		"GET /api/dcim/interfaces/",
+		"PATCH /api/dcim/interfaces/7/",
+		"GET /api/dcim/interfaces/7/",
+	}, methods)
+}
+
+func TestGetWritableDeviceInterfaceBridge(t *testing.T) {
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxDeviceInterface().Schema, map[string]interface{}{
+		"name":      "br0",
+		"device_id": 3,
+		"type":      "bridge",
+		"bridge_id": 8,
+	})
+
+	api := &providerState{netboxVersion: semver.MustParse("3.2.1")}
+	_, fields, diags := getWritableDeviceInterfaceFromResourceData(api, d)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, int64(8), fields["bridge"])
+
+	api.netboxVersion = semver.MustParse("3.1.11")
+	_, fields, diags = getWritableDeviceInterfaceFromResourceData(api, d)
+	assert.False(t, diags.HasError())
+	assert.NotContains(t, fields, "bridge")
+}
+
+func init() {
+	resource.AddTestSweepers("netbox_device_interface", &resource.Sweeper{
+		Name:         "netbox_device_interface",
+		Dependencies: []string{},
+		F: func(region string) error {
+			m, err := sharedClientForRegion(region)
+			if err != nil {
+				return fmt.Errorf("Error getting client: %s", err)
+			}
+			api := m.(*client.NetBoxAPI)
+			params := dcim.NewDcimInterfacesListParams()
+			res, err := api.Dcim.DcimInterfacesList(params, nil)
+			if err != nil {
+				return err
+			}
+			for _, iface := range res.GetPayload().Results {
+				if iface.Device != nil && strings.HasPrefix(*iface.Device.Name, testPrefix) {
+					deleteParams := dcim.NewDcimInterfacesDeleteParams().WithID(iface.ID)
+					_, err := api.Dcim.DcimInterfacesDelete(deleteParams, nil)
+					if err != nil && !isNotFoundError(err) {
+						return err
+					}
+					log.Print("[DEBUG] Deleted a device interface")
+				}
+			}
+			return nil
+		},
+	})
+}
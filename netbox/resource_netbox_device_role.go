@@ -1,20 +1,22 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/dcim"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceNetboxDeviceRole() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxDeviceRoleCreate,
-		Read:   resourceNetboxDeviceRoleRead,
-		Update: resourceNetboxDeviceRoleUpdate,
-		Delete: resourceNetboxDeviceRoleDelete,
+		CreateContext: resourceNetboxDeviceRoleCreate,
+		ReadContext:   resourceNetboxDeviceRoleRead,
+		UpdateContext: resourceNetboxDeviceRoleUpdate,
+		DeleteContext: resourceNetboxDeviceRoleDelete,
+		CustomizeDiff: customizeDiffCustomFieldsAll,
 
 		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/devices/#device-roles):
 
@@ -39,15 +41,15 @@ func resourceNetboxDeviceRole() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer: naturalKeyImporter("netbox_device_role", "a slug", lookupDeviceRolesBySlug),
 	}
 }
 
-func resourceNetboxDeviceRoleCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxDeviceRoleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	name := d.Get("name").(string)
 	slugValue, slugOk := d.GetOk("slug")
@@ -63,51 +65,60 @@ func resourceNetboxDeviceRoleCreate(d *schema.ResourceData, m interface{}) error
 	color := d.Get("color_hex").(string)
 	vmRole := d.Get("vm_role").(bool)
 
-	params := dcim.NewDcimDeviceRolesCreateParams().WithData(
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	params := dcim.NewDcimDeviceRolesCreateParamsWithContext(ctx).WithData(
 		&models.DeviceRole{
-			Name:   &name,
-			Slug:   &slug,
-			Color:  color,
-			VMRole: vmRole,
+			Name:         &name,
+			Slug:         &slug,
+			Color:        color,
+			VMRole:       vmRole,
+			CustomFields: customFields,
 		},
 	)
 
 	res, err := api.Dcim.DcimDeviceRolesCreate(params, nil)
 	if err != nil {
 		//return errors.New(getTextFromError(err))
-		return err
+		return diagFromNetboxError(err, resourceNetboxDeviceRole().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxDeviceRoleRead(d, m)
+	return resourceNetboxDeviceRoleRead(ctx, d, m)
 }
 
-func resourceNetboxDeviceRoleRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxDeviceRoleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := dcim.NewDcimDeviceRolesReadParams().WithID(id)
+	params := dcim.NewDcimDeviceRolesReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Dcim.DcimDeviceRolesRead(params, nil)
 	if err != nil {
-		errorcode := err.(*dcim.DcimDeviceRolesReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxDeviceRole().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
 	d.Set("slug", res.GetPayload().Slug)
 	d.Set("vm_role", res.GetPayload().VMRole)
 	d.Set("color_hex", res.GetPayload().Color)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
-func resourceNetboxDeviceRoleUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxDeviceRoleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.DeviceRole{}
@@ -131,25 +142,31 @@ func resourceNetboxDeviceRoleUpdate(d *schema.ResourceData, m interface{}) error
 	data.VMRole = vmRole
 	data.Color = color
 
-	params := dcim.NewDcimDeviceRolesPartialUpdateParams().WithID(id).WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := dcim.NewDcimDeviceRolesPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Dcim.DcimDeviceRolesPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxDeviceRole().Schema)
 	}
 
-	return resourceNetboxDeviceRoleRead(d, m)
+	return resourceNetboxDeviceRoleRead(ctx, d, m)
 }
 
-func resourceNetboxDeviceRoleDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxDeviceRoleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := dcim.NewDcimDeviceRolesDeleteParams().WithID(id)
+	params := dcim.NewDcimDeviceRolesDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Dcim.DcimDeviceRolesDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxDeviceRole().Schema)
 	}
 	return nil
 }
@@ -88,9 +88,83 @@ resource "netbox_device" "test" {
 	})
 }
 
+func TestAccNetboxDevice_rack(t *testing.T) {
+
+	testSlug := "device_rack"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDeviceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNetboxDeviceFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_location" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+}
+
+resource "netbox_rack" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+  location_id = netbox_location.test.id
+}
+
+resource "netbox_device" "test" {
+  name = "%[1]s"
+  role_id = netbox_device_role.test.id
+  device_type_id = netbox_device_type.test.id
+  site_id = netbox_site.test.id
+  location_id = netbox_location.test.id
+  rack_id = netbox_rack.test.id
+  position = 10
+  face = "front"
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("netbox_device.test", "location_id", "netbox_location.test", "id"),
+					resource.TestCheckResourceAttrPair("netbox_device.test", "rack_id", "netbox_rack.test", "id"),
+					resource.TestCheckResourceAttr("netbox_device.test", "position", "10"),
+					resource.TestCheckResourceAttr("netbox_device.test", "face", "front"),
+				),
+			},
+			{
+				Config: testAccNetboxDeviceFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_location" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+}
+
+resource "netbox_rack" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+  location_id = netbox_location.test.id
+}
+
+resource "netbox_device" "test" {
+  name = "%[1]s"
+  role_id = netbox_device_role.test.id
+  device_type_id = netbox_device_type.test.id
+  site_id = netbox_site.test.id
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_device.test", "location_id", "0"),
+					resource.TestCheckResourceAttr("netbox_device.test", "rack_id", "0"),
+					resource.TestCheckResourceAttr("netbox_device.test", "position", "0"),
+					resource.TestCheckResourceAttr("netbox_device.test", "face", ""),
+				),
+			},
+			{
+				ResourceName:      "netbox_device.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccCheckDeviceDestroy(s *terraform.State) error {
 	// retrieve the connection established in Provider configuration
-	conn := testAccProvider.Meta().(*client.NetBoxAPI)
+	conn := testAccProvider.Meta().(*providerState)
 
 	// loop through the resources in state, verifying each device
 	// is destroyed
@@ -109,8 +183,7 @@ func testAccCheckDeviceDestroy(s *terraform.State) error {
 		}
 
 		if err != nil {
-			errorcode := err.(*dcim.DcimDevicesReadDefault).Code()
-			if errorcode == 404 {
+			if isNotFoundError(err) {
 				return nil
 			}
 			return err
@@ -1,22 +1,25 @@
 package netbox
 
 import (
+	"context"
 	"regexp"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/virtualization"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxInterface() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxInterfaceCreate,
-		Read:   resourceNetboxInterfaceRead,
-		Update: resourceNetboxInterfaceUpdate,
-		Delete: resourceNetboxInterfaceDelete,
+		CreateContext: resourceNetboxInterfaceCreate,
+		ReadContext:   resourceNetboxInterfaceRead,
+		UpdateContext: resourceNetboxInterfaceUpdate,
+		DeleteContext: resourceNetboxInterfaceDelete,
+		CustomizeDiff: customdiff.All(customizeDiffTagsAll, customizeDiffCustomFieldsAll),
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -52,6 +55,9 @@ func resourceNetboxInterface() *schema.Resource {
 				Optional: true,
 				Set:      schema.HashString,
 			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -59,102 +65,120 @@ func resourceNetboxInterface() *schema.Resource {
 	}
 }
 
-func resourceNetboxInterfaceCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxInterfaceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	name := d.Get("name").(string)
 	virtualMachineID := int64(d.Get("virtual_machine_id").(int))
 	description := d.Get("description").(string)
 	macAddress := d.Get("mac_address").(string)
-	tags, _ := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
 
 	data := models.WritableVMInterface{
 		Name:           &name,
 		Description:    description,
 		VirtualMachine: &virtualMachineID,
 		Tags:           tags,
+		CustomFields:   customFields,
 		TaggedVlans:    []int64{},
 	}
 	if macAddress != "" {
 		data.MacAddress = &macAddress
 	}
-	params := virtualization.NewVirtualizationInterfacesCreateParams().WithData(&data)
+	params := virtualization.NewVirtualizationInterfacesCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Virtualization.VirtualizationInterfacesCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxInterface().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxInterfaceUpdate(d, m)
+	return resourceNetboxInterfaceUpdate(ctx, d, m)
 }
 
-func resourceNetboxInterfaceRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxInterfaceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 
-	params := virtualization.NewVirtualizationInterfacesReadParams().WithID(id)
+	params := virtualization.NewVirtualizationInterfacesReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Virtualization.VirtualizationInterfacesRead(params, nil)
 	if err != nil {
-		errorcode := err.(*virtualization.VirtualizationInterfacesReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxInterface().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
 	d.Set("virtual_machine_id", res.GetPayload().VirtualMachine.ID)
 	d.Set("description", res.GetPayload().Description)
 	d.Set("mac_address", res.GetPayload().MacAddress)
-	d.Set("tags", getTagListFromNestedTagList(res.GetPayload().Tags))
+	setTagsFromNestedTagList(api, d, res.GetPayload().Tags)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
 	return nil
 }
 
-func resourceNetboxInterfaceUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxInterfaceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 
 	name := d.Get("name").(string)
 	virtualMachineID := int64(d.Get("virtual_machine_id").(int))
 	description := d.Get("description").(string)
-	tags, _ := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
 
 	data := models.WritableVMInterface{
 		Name:           &name,
 		Description:    description,
 		VirtualMachine: &virtualMachineID,
 		Tags:           tags,
+		CustomFields:   customFields,
 		TaggedVlans:    []int64{},
 	}
 
-	params := virtualization.NewVirtualizationInterfacesPartialUpdateParams().WithID(id).WithData(&data)
+	params := virtualization.NewVirtualizationInterfacesPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 	if d.HasChange("mac_address") {
 		macAddress := d.Get("mac_address").(string)
 		data.MacAddress = &macAddress
 	}
 	_, err := api.Virtualization.VirtualizationInterfacesPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxInterface().Schema)
 	}
 
-	return resourceNetboxInterfaceRead(d, m)
+	return resourceNetboxInterfaceRead(ctx, d, m)
 }
 
-func resourceNetboxInterfaceDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxInterfaceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := virtualization.NewVirtualizationInterfacesDeleteParams().WithID(id)
+	params := virtualization.NewVirtualizationInterfacesDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Virtualization.VirtualizationInterfacesDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxInterface().Schema)
 	}
 	return nil
 }
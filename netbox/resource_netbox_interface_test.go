@@ -95,7 +95,7 @@ resource "netbox_interface" "test" {
 
 func testAccCheckInterfaceDestroy(s *terraform.State) error {
 	// retrieve the connection established in Provider configuration
-	conn := testAccProvider.Meta().(*client.NetBoxAPI)
+	conn := testAccProvider.Meta().(*providerState)
 
 	// loop through the resources in state, verifying each interface
 	// is destroyed
@@ -114,8 +114,7 @@ func testAccCheckInterfaceDestroy(s *terraform.State) error {
 		}
 
 		if err != nil {
-			errorcode := err.(*virtualization.VirtualizationInterfacesReadDefault).Code()
-			if errorcode == 404 {
+			if isNotFoundError(err) {
 				return nil
 			}
 			return err
@@ -1,21 +1,23 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/ipam"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxIpamRole() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxIpamRoleCreate,
-		Read:   resourceNetboxIpamRoleRead,
-		Update: resourceNetboxIpamRoleUpdate,
-		Delete: resourceNetboxIpamRoleDelete,
+		CreateContext: resourceNetboxIpamRoleCreate,
+		ReadContext:   resourceNetboxIpamRoleRead,
+		UpdateContext: resourceNetboxIpamRoleUpdate,
+		DeleteContext: resourceNetboxIpamRoleDelete,
+		CustomizeDiff: customizeDiffCustomFieldsAll,
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -37,14 +39,14 @@ func resourceNetboxIpamRole() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer: naturalKeyImporter("netbox_ipam_role", "a slug", lookupIpamRolesBySlug),
 	}
 }
-func resourceNetboxIpamRoleCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxIpamRoleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	data := models.Role{}
 
 	name := d.Get("name").(string)
@@ -65,30 +67,35 @@ func resourceNetboxIpamRoleCreate(d *schema.ResourceData, m interface{}) error {
 	data.Weight = &weight
 	data.Description = description
 
-	params := ipam.NewIpamRolesCreateParams().WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := ipam.NewIpamRolesCreateParamsWithContext(ctx).WithData(&data)
 	res, err := api.Ipam.IpamRolesCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxIpamRole().Schema)
 	}
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxIpamRoleUpdate(d, m)
+	return resourceNetboxIpamRoleUpdate(ctx, d, m)
 }
 
-func resourceNetboxIpamRoleRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxIpamRoleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := ipam.NewIpamRolesReadParams().WithID(id)
+	params := ipam.NewIpamRolesReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Ipam.IpamRolesRead(params, nil)
 	if err != nil {
-		errorcode := err.(*ipam.IpamRolesReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxIpamRole().Schema)
 	}
 
 	if res.GetPayload().Name != nil {
@@ -107,11 +114,15 @@ func resourceNetboxIpamRoleRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("description", res.GetPayload().Description)
 	}
 
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
-func resourceNetboxIpamRoleUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxIpamRoleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.Role{}
 
@@ -133,21 +144,27 @@ func resourceNetboxIpamRoleUpdate(d *schema.ResourceData, m interface{}) error {
 	data.Weight = &weight
 	data.Description = description
 
-	params := ipam.NewIpamRolesUpdateParams().WithID(id).WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := ipam.NewIpamRolesUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 	_, err := api.Ipam.IpamRolesUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxIpamRole().Schema)
 	}
-	return resourceNetboxIpamRoleRead(d, m)
+	return resourceNetboxIpamRoleRead(ctx, d, m)
 }
 
-func resourceNetboxIpamRoleDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxIpamRoleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := ipam.NewIpamRolesDeleteParams().WithID(id)
+	params := ipam.NewIpamRolesDeleteParamsWithContext(ctx).WithID(id)
 	_, err := api.Ipam.IpamRolesDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxIpamRole().Schema)
 	}
 	d.SetId("")
 	return nil
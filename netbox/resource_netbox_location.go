@@ -0,0 +1,271 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// locationFields are the fields of a location that the models of the
+// go-netbox client lack.
+type locationFields struct {
+	Status *struct {
+		Value string `json:"value"`
+	} `json:"status"`
+	Tenant *models.NestedTenant `json:"tenant"`
+	Tags   []*models.NestedTag  `json:"tags"`
+}
+
+func resourceNetboxLocation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNetboxLocationCreate,
+		ReadContext:   resourceNetboxLocationRead,
+		UpdateContext: resourceNetboxLocationUpdate,
+		DeleteContext: resourceNetboxLocationDelete,
+		CustomizeDiff: customdiff.All(customizeDiffTagsAll, customizeDiffDefaultTenant, customizeDiffCustomFieldsAll),
+
+		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/sites-and-racks/#locations):
+
+> Racks and devices can be grouped by location within a site. A location may represent a floor, room, cage, or similar organizational unit. Locations can be nested to form a hierarchy. For example, you may have floors within a site, and rooms within a floor.`,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"slug": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringLenBetween(0, 100),
+			},
+			"site_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"parent_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"status": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "active",
+				ValidateFunc: validation.StringInSlice([]string{"planned", "staging", "active", "decommissioning", "retired"}, false),
+			},
+			"tenant_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"description": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringLenBetween(0, 200),
+			},
+			"tags": &schema.Schema{
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				Set:      schema.HashString,
+			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
+		},
+		Importer: naturalKeyImporter("netbox_location", "slug or slug@site", lookupLocationsBySlug),
+	}
+}
+
+// getWritableLocationFromResourceData returns the location to create or
+// update, and the fields that the model lacks.
+func getWritableLocationFromResourceData(api *providerState, d *schema.ResourceData) (*models.WritableLocation, map[string]interface{}, diag.Diagnostics) {
+	name := d.Get("name").(string)
+	siteID := int64(d.Get("site_id").(int))
+
+	data := models.WritableLocation{
+		Name:        &name,
+		Site:        &siteID,
+		Description: d.Get("description").(string),
+	}
+
+	slugValue, slugOk := d.GetOk("slug")
+	// Default slug to name if not given
+	if !slugOk {
+		data.Slug = strToPtr(name)
+	} else {
+		data.Slug = strToPtr(slugValue.(string))
+	}
+
+	if d.HasChange("description") && data.Description == "" {
+		// description omits empty values so set to ' '
+		data.Description = " "
+	}
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	data.CustomFields = customFields
+
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+
+	// parent and tenant are sent as null if unset to remove them on update
+	fields := map[string]interface{}{
+		"status": d.Get("status").(string),
+		"parent": nil,
+		"tenant": nil,
+		"tags":   tags,
+	}
+
+	if parentID, ok := d.GetOk("parent_id"); ok {
+		fields["parent"] = int64(parentID.(int))
+	}
+
+	if tenantID, ok := d.GetOk("tenant_id"); ok {
+		fields["tenant"] = int64(tenantID.(int))
+	}
+
+	return &data, fields, nil
+}
+
+func resourceNetboxLocationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	data, fields, diags := getWritableLocationFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	params := dcim.NewDcimLocationsCreateParamsWithContext(ctx).WithData(data)
+
+	res, err := api.Dcim.DcimLocationsCreate(params, nil, withRequestFields(fields))
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxLocation().Schema)
+	}
+
+	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
+
+	return resourceNetboxLocationRead(ctx, d, m)
+}
+
+func resourceNetboxLocationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimLocationsReadParamsWithContext(ctx).WithID(id)
+
+	var fields locationFields
+	res, err := api.Dcim.DcimLocationsRead(params, nil, withResponseFields(&fields))
+	if err != nil {
+		if isNotFoundError(err) {
+			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
+			d.SetId("")
+			return nil
+		}
+		return diagFromNetboxError(err, resourceNetboxLocation().Schema)
+	}
+
+	location := res.GetPayload()
+
+	d.Set("name", location.Name)
+	d.Set("slug", location.Slug)
+	d.Set("description", location.Description)
+
+	if location.Site != nil {
+		d.Set("site_id", location.Site.ID)
+	} else {
+		d.Set("site_id", nil)
+	}
+
+	if location.Parent != nil {
+		d.Set("parent_id", location.Parent.ID)
+	} else {
+		d.Set("parent_id", nil)
+	}
+
+	if fields.Status != nil {
+		d.Set("status", fields.Status.Value)
+	}
+
+	if fields.Tenant != nil {
+		d.Set("tenant_id", fields.Tenant.ID)
+	} else {
+		d.Set("tenant_id", nil)
+	}
+
+	setTagsFromNestedTagList(api, d, fields.Tags)
+	if err := setCustomFieldsFromAPI(api, d, location.CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetboxLocationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+
+	data, fields, diags := getWritableLocationFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	params := dcim.NewDcimLocationsPartialUpdateParamsWithContext(ctx).WithID(id).WithData(data)
+
+	_, err := api.Dcim.DcimLocationsPartialUpdate(params, nil, withRequestFields(fields))
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxLocation().Schema)
+	}
+
+	return resourceNetboxLocationRead(ctx, d, m)
+}
+
+func resourceNetboxLocationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+
+	// Netbox deletes the child locations along with their parent, which
+	// would silently destroy objects managed elsewhere
+	childParams := dcim.NewDcimLocationsListParamsWithContext(ctx)
+	parentID := d.Id()
+	childParams.ParentID = &parentID
+
+	children, err := api.Dcim.DcimLocationsList(childParams, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxLocation().Schema)
+	}
+	if len(children.GetPayload().Results) > 0 {
+		var names []string
+		for _, child := range children.GetPayload().Results {
+			names = append(names, *child.Name)
+		}
+		return diag.Diagnostics{diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Location still has child locations",
+			Detail:   fmt.Sprintf("Location %s cannot be deleted because Netbox would delete its child locations along with it: %s. Delete the child locations or move them to another parent first.", d.Get("name").(string), strings.Join(names, ", ")),
+		}}
+	}
+
+	params := dcim.NewDcimLocationsDeleteParamsWithContext(ctx).WithID(id)
+
+	_, err = api.Dcim.DcimLocationsDelete(params, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxLocation().Schema)
+	}
+	return nil
+}
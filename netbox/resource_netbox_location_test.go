@@ -0,0 +1,158 @@
+package netbox
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func testAccNetboxLocationFullDependencies(testName string) string {
+	return fmt.Sprintf(`
+resource "netbox_tenant" "test" {
+  name = "%[1]s"
+}
+
+resource "netbox_site" "test" {
+  name = "%[1]s"
+  status = "active"
+}
+
+resource "netbox_tag" "test" {
+  name = "%[1]s"
+}`, testName)
+}
+
+func TestAccNetboxLocation_basic(t *testing.T) {
+
+	testSlug := "location_basic"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNetboxLocationFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_location" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+  tenant_id = netbox_tenant.test.id
+  status = "planned"
+  description = "my-description"
+  tags = [netbox_tag.test.name]
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_location.test", "name", testName),
+					resource.TestCheckResourceAttr("netbox_location.test", "slug", testName),
+					resource.TestCheckResourceAttrPair("netbox_location.test", "site_id", "netbox_site.test", "id"),
+					resource.TestCheckResourceAttrPair("netbox_location.test", "tenant_id", "netbox_tenant.test", "id"),
+					resource.TestCheckResourceAttr("netbox_location.test", "status", "planned"),
+					resource.TestCheckResourceAttr("netbox_location.test", "description", "my-description"),
+					resource.TestCheckResourceAttr("netbox_location.test", "tags.#", "1"),
+					resource.TestCheckResourceAttr("netbox_location.test", "tags.0", testName),
+				),
+			},
+			{
+				Config: testAccNetboxLocationFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_location" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_location.test", "tenant_id", "0"),
+					resource.TestCheckResourceAttr("netbox_location.test", "status", "active"),
+					resource.TestCheckResourceAttr("netbox_location.test", "description", ""),
+					resource.TestCheckResourceAttr("netbox_location.test", "tags.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "netbox_location.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "netbox_location.test",
+				ImportState:       true,
+				ImportStateId:     testName + "@" + testName,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccNetboxLocation_parent(t *testing.T) {
+
+	testSlug := "location_parent"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNetboxLocationFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_location" "parent" {
+  name = "%[1]s-parent"
+  site_id = netbox_site.test.id
+}
+
+resource "netbox_location" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+  parent_id = netbox_location.parent.id
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("netbox_location.test", "parent_id", "netbox_location.parent", "id"),
+				),
+			},
+			{
+				Config: testAccNetboxLocationFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_location" "parent" {
+  name = "%[1]s-parent"
+  site_id = netbox_site.test.id
+}
+
+resource "netbox_location" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_location.test", "parent_id", "0"),
+				),
+			},
+		},
+	})
+}
+
+func init() {
+	resource.AddTestSweepers("netbox_location", &resource.Sweeper{
+		Name:         "netbox_location",
+		Dependencies: []string{"netbox_rack", "netbox_device"},
+		F: func(region string) error {
+			m, err := sharedClientForRegion(region)
+			if err != nil {
+				return fmt.Errorf("Error getting client: %s", err)
+			}
+			api := m.(*client.NetBoxAPI)
+			params := dcim.NewDcimLocationsListParams()
+			res, err := api.Dcim.DcimLocationsList(params, nil)
+			if err != nil {
+				return err
+			}
+			for _, location := range res.GetPayload().Results {
+				if strings.HasPrefix(*location.Name, testPrefix) {
+					deleteParams := dcim.NewDcimLocationsDeleteParams().WithID(location.ID)
+					_, err := api.Dcim.DcimLocationsDelete(deleteParams, nil)
+					if err != nil && !isNotFoundError(err) {
+						return err
+					}
+					log.Print("[DEBUG] Deleted a location")
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -1,21 +1,23 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/dcim"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxManufacturer() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxManufacturerCreate,
-		Read:   resourceNetboxManufacturerRead,
-		Update: resourceNetboxManufacturerUpdate,
-		Delete: resourceNetboxManufacturerDelete,
+		CreateContext: resourceNetboxManufacturerCreate,
+		ReadContext:   resourceNetboxManufacturerRead,
+		UpdateContext: resourceNetboxManufacturerUpdate,
+		DeleteContext: resourceNetboxManufacturerDelete,
+		CustomizeDiff: customizeDiffCustomFieldsAll,
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -28,6 +30,8 @@ func resourceNetboxManufacturer() *schema.Resource {
 				Computed:     true,
 				ValidateFunc: validation.StringLenBetween(0, 30),
 			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -35,8 +39,8 @@ func resourceNetboxManufacturer() *schema.Resource {
 	}
 }
 
-func resourceNetboxManufacturerCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxManufacturerCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	data := models.Manufacturer{}
 
@@ -51,43 +55,52 @@ func resourceNetboxManufacturerCreate(d *schema.ResourceData, m interface{}) err
 		data.Slug = strToPtr(slugValue.(string))
 	}
 
-	params := dcim.NewDcimManufacturersCreateParams().WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := dcim.NewDcimManufacturersCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Dcim.DcimManufacturersCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxManufacturer().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxManufacturerRead(d, m)
+	return resourceNetboxManufacturerRead(ctx, d, m)
 }
 
-func resourceNetboxManufacturerRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxManufacturerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := dcim.NewDcimManufacturersReadParams().WithID(id)
+	params := dcim.NewDcimManufacturersReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Dcim.DcimManufacturersRead(params, nil)
 
 	if err != nil {
-		errorcode := err.(*dcim.DcimManufacturersReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxManufacturer().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
 	d.Set("slug", res.GetPayload().Slug)
 
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
-func resourceNetboxManufacturerUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxManufacturerUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.Manufacturer{}
@@ -103,25 +116,31 @@ func resourceNetboxManufacturerUpdate(d *schema.ResourceData, m interface{}) err
 		data.Slug = strToPtr(slugValue.(string))
 	}
 
-	params := dcim.NewDcimManufacturersPartialUpdateParams().WithID(id).WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := dcim.NewDcimManufacturersPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Dcim.DcimManufacturersPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxManufacturer().Schema)
 	}
 
-	return resourceNetboxManufacturerRead(d, m)
+	return resourceNetboxManufacturerRead(ctx, d, m)
 }
 
-func resourceNetboxManufacturerDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxManufacturerDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := dcim.NewDcimManufacturersDeleteParams().WithID(id)
+	params := dcim.NewDcimManufacturersDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Dcim.DcimManufacturersDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxManufacturer().Schema)
 	}
 	return nil
 }
@@ -1,21 +1,23 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/dcim"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxPlatform() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxPlatformCreate,
-		Read:   resourceNetboxPlatformRead,
-		Update: resourceNetboxPlatformUpdate,
-		Delete: resourceNetboxPlatformDelete,
+		CreateContext: resourceNetboxPlatformCreate,
+		ReadContext:   resourceNetboxPlatformRead,
+		UpdateContext: resourceNetboxPlatformUpdate,
+		DeleteContext: resourceNetboxPlatformDelete,
+		CustomizeDiff: customizeDiffCustomFieldsAll,
 
 		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/devices/#platforms):
 
@@ -32,15 +34,15 @@ func resourceNetboxPlatform() *schema.Resource {
 				Computed:     true,
 				ValidateFunc: validation.StringLenBetween(0, 30),
 			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer: naturalKeyImporter("netbox_platform", "a slug", lookupPlatformsBySlug),
 	}
 }
 
-func resourceNetboxPlatformCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxPlatformCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	name := d.Get("name").(string)
 
@@ -53,48 +55,57 @@ func resourceNetboxPlatformCreate(d *schema.ResourceData, m interface{}) error {
 		slug = slugValue.(string)
 	}
 
-	params := dcim.NewDcimPlatformsCreateParams().WithData(
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	params := dcim.NewDcimPlatformsCreateParamsWithContext(ctx).WithData(
 		&models.WritablePlatform{
-			Name: &name,
-			Slug: &slug,
+			Name:         &name,
+			Slug:         &slug,
+			CustomFields: customFields,
 		},
 	)
 
 	res, err := api.Dcim.DcimPlatformsCreate(params, nil)
 	if err != nil {
 		//return errors.New(getTextFromError(err))
-		return err
+		return diagFromNetboxError(err, resourceNetboxPlatform().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxPlatformRead(d, m)
+	return resourceNetboxPlatformRead(ctx, d, m)
 }
 
-func resourceNetboxPlatformRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxPlatformRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := dcim.NewDcimPlatformsReadParams().WithID(id)
+	params := dcim.NewDcimPlatformsReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Dcim.DcimPlatformsRead(params, nil)
 
 	if err != nil {
-		errorcode := err.(*dcim.DcimPlatformsReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxPlatform().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
 	d.Set("slug", res.GetPayload().Slug)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
-func resourceNetboxPlatformUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxPlatformUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.WritablePlatform{}
@@ -113,25 +124,31 @@ func resourceNetboxPlatformUpdate(d *schema.ResourceData, m interface{}) error {
 	data.Slug = &slug
 	data.Name = &name
 
-	params := dcim.NewDcimPlatformsPartialUpdateParams().WithID(id).WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := dcim.NewDcimPlatformsPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Dcim.DcimPlatformsPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxPlatform().Schema)
 	}
 
-	return resourceNetboxPlatformRead(d, m)
+	return resourceNetboxPlatformRead(ctx, d, m)
 }
 
-func resourceNetboxPlatformDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxPlatformDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := dcim.NewDcimPlatformsDeleteParams().WithID(id)
+	params := dcim.NewDcimPlatformsDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Dcim.DcimPlatformsDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxPlatform().Schema)
 	}
 	return nil
 }
@@ -0,0 +1,269 @@
+package netbox
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceNetboxRack() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNetboxRackCreate,
+		ReadContext:   resourceNetboxRackRead,
+		UpdateContext: resourceNetboxRackUpdate,
+		DeleteContext: resourceNetboxRackDelete,
+		CustomizeDiff: customdiff.All(customizeDiffTagsAll, customizeDiffDefaultTenant, customizeDiffCustomFieldsAll),
+
+		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/sites-and-racks/#racks):
+
+> The rack model represents a physical two- or four-post equipment rack in which devices can be installed. Each rack must be assigned to a site, and may optionally be assigned to a location and/or tenant. Racks can also be organized by user-defined functional roles.`,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"site_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"location_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"role_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"tenant_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"status": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "active",
+				ValidateFunc: validation.StringInSlice([]string{"reserved", "available", "planned", "active", "deprecated"}, false),
+			},
+			"width": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      19,
+				ValidateFunc: validation.IntInSlice([]int{10, 19, 21, 23}),
+				Description:  "Rail-to-rail width in inches.",
+			},
+			"u_height": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      42,
+				ValidateFunc: validation.IntBetween(1, 100),
+				Description:  "Height in rack units.",
+			},
+			"serial": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"asset_tag": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": &schema.Schema{
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				Set:      schema.HashString,
+			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+	}
+}
+
+// getWritableRackFromResourceData returns the rack to create or update, and
+// the fields that the model omits if they are unset.
+func getWritableRackFromResourceData(api *providerState, d *schema.ResourceData) (*models.WritableRack, map[string]interface{}, diag.Diagnostics) {
+	name := d.Get("name").(string)
+	siteID := int64(d.Get("site_id").(int))
+
+	data := models.WritableRack{
+		Name:    &name,
+		Site:    &siteID,
+		Status:  d.Get("status").(string),
+		Width:   int64(d.Get("width").(int)),
+		UHeight: int64(d.Get("u_height").(int)),
+		Serial:  d.Get("serial").(string),
+	}
+
+	if d.HasChange("serial") && data.Serial == "" {
+		// serial omits empty values so set to ' '
+		data.Serial = " "
+	}
+
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	data.Tags = tags
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	data.CustomFields = customFields
+
+	// location, role, tenant and asset tag are sent as null if unset to remove
+	// them on update
+	fields := map[string]interface{}{
+		"location":  nil,
+		"role":      nil,
+		"tenant":    nil,
+		"asset_tag": nil,
+	}
+
+	if locationID, ok := d.GetOk("location_id"); ok {
+		fields["location"] = int64(locationID.(int))
+	}
+
+	if roleID, ok := d.GetOk("role_id"); ok {
+		fields["role"] = int64(roleID.(int))
+	}
+
+	if tenantID, ok := d.GetOk("tenant_id"); ok {
+		fields["tenant"] = int64(tenantID.(int))
+	}
+
+	if assetTag, ok := d.GetOk("asset_tag"); ok {
+		fields["asset_tag"] = assetTag.(string)
+	}
+
+	return &data, fields, nil
+}
+
+func resourceNetboxRackCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	data, fields, diags := getWritableRackFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	params := dcim.NewDcimRacksCreateParamsWithContext(ctx).WithData(data)
+
+	res, err := api.Dcim.DcimRacksCreate(params, nil, withRequestFields(fields))
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxRack().Schema)
+	}
+
+	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
+
+	return resourceNetboxRackRead(ctx, d, m)
+}
+
+func resourceNetboxRackRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimRacksReadParamsWithContext(ctx).WithID(id)
+
+	res, err := api.Dcim.DcimRacksRead(params, nil)
+	if err != nil {
+		if isNotFoundError(err) {
+			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
+			d.SetId("")
+			return nil
+		}
+		return diagFromNetboxError(err, resourceNetboxRack().Schema)
+	}
+
+	rack := res.GetPayload()
+
+	d.Set("name", rack.Name)
+
+	if rack.Site != nil {
+		d.Set("site_id", rack.Site.ID)
+	} else {
+		d.Set("site_id", nil)
+	}
+
+	if rack.Location != nil {
+		d.Set("location_id", rack.Location.ID)
+	} else {
+		d.Set("location_id", nil)
+	}
+
+	if rack.Role != nil {
+		d.Set("role_id", rack.Role.ID)
+	} else {
+		d.Set("role_id", nil)
+	}
+
+	if rack.Tenant != nil {
+		d.Set("tenant_id", rack.Tenant.ID)
+	} else {
+		d.Set("tenant_id", nil)
+	}
+
+	if rack.Status != nil {
+		d.Set("status", rack.Status.Value)
+	}
+
+	if rack.Width != nil {
+		d.Set("width", rack.Width.Value)
+	}
+
+	d.Set("u_height", rack.UHeight)
+	d.Set("serial", rack.Serial)
+	d.Set("asset_tag", rack.AssetTag)
+
+	setTagsFromNestedTagList(api, d, rack.Tags)
+	if err := setCustomFieldsFromAPI(api, d, rack.CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetboxRackUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+
+	data, fields, diags := getWritableRackFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+
+	params := dcim.NewDcimRacksPartialUpdateParamsWithContext(ctx).WithID(id).WithData(data)
+
+	_, err := api.Dcim.DcimRacksPartialUpdate(params, nil, withRequestFields(fields))
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxRack().Schema)
+	}
+
+	return resourceNetboxRackRead(ctx, d, m)
+}
+
+func resourceNetboxRackDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimRacksDeleteParamsWithContext(ctx).WithID(id)
+
+	_, err := api.Dcim.DcimRacksDelete(params, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxRack().Schema)
+	}
+	return nil
+}
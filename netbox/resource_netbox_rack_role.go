@@ -0,0 +1,175 @@
+package netbox
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceNetboxRackRole() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNetboxRackRoleCreate,
+		ReadContext:   resourceNetboxRackRoleRead,
+		UpdateContext: resourceNetboxRackRoleUpdate,
+		DeleteContext: resourceNetboxRackRoleDelete,
+		CustomizeDiff: customizeDiffCustomFieldsAll,
+
+		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/sites-and-racks/#rack-roles):
+
+> Each rack can optionally be assigned a user-defined functional role. For example, you might designate a rack for compute or storage resources, or to house colocated customer devices. Rack roles are fully customizable and may be color-coded.`,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"slug": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringLenBetween(0, 100),
+			},
+			"color_hex": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
+		},
+		Importer: naturalKeyImporter("netbox_rack_role", "a slug", lookupRackRolesBySlug),
+	}
+}
+
+func resourceNetboxRackRoleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	name := d.Get("name").(string)
+	slugValue, slugOk := d.GetOk("slug")
+	var slug string
+
+	// Default slug to name if not given
+	if !slugOk {
+		slug = name
+	} else {
+		slug = slugValue.(string)
+	}
+
+	data := models.RackRole{
+		Name:        &name,
+		Slug:        &slug,
+		Color:       d.Get("color_hex").(string),
+		Description: d.Get("description").(string),
+	}
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := dcim.NewDcimRackRolesCreateParamsWithContext(ctx).WithData(&data)
+
+	res, err := api.Dcim.DcimRackRolesCreate(params, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxRackRole().Schema)
+	}
+
+	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
+
+	return resourceNetboxRackRoleRead(ctx, d, m)
+}
+
+func resourceNetboxRackRoleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimRackRolesReadParamsWithContext(ctx).WithID(id)
+
+	res, err := api.Dcim.DcimRackRolesRead(params, nil)
+	if err != nil {
+		if isNotFoundError(err) {
+			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
+			d.SetId("")
+			return nil
+		}
+		return diagFromNetboxError(err, resourceNetboxRackRole().Schema)
+	}
+
+	d.Set("name", res.GetPayload().Name)
+	d.Set("slug", res.GetPayload().Slug)
+	d.Set("color_hex", res.GetPayload().Color)
+	d.Set("description", res.GetPayload().Description)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetboxRackRoleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+
+	name := d.Get("name").(string)
+	slugValue, slugOk := d.GetOk("slug")
+	var slug string
+
+	// Default slug to name if not given
+	if !slugOk {
+		slug = name
+	} else {
+		slug = slugValue.(string)
+	}
+
+	data := models.RackRole{
+		Name:  &name,
+		Slug:  &slug,
+		Color: d.Get("color_hex").(string),
+	}
+
+	if d.HasChange("description") {
+		// description omits empty values so set to ' '
+		if description := d.Get("description"); description.(string) == "" {
+			data.Description = " "
+		} else {
+			data.Description = description.(string)
+		}
+	}
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := dcim.NewDcimRackRolesPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
+
+	_, err := api.Dcim.DcimRackRolesPartialUpdate(params, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxRackRole().Schema)
+	}
+
+	return resourceNetboxRackRoleRead(ctx, d, m)
+}
+
+func resourceNetboxRackRoleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimRackRolesDeleteParamsWithContext(ctx).WithID(id)
+
+	_, err := api.Dcim.DcimRackRolesDelete(params, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxRackRole().Schema)
+	}
+	return nil
+}
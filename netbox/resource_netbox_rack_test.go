@@ -0,0 +1,134 @@
+package netbox
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func testAccNetboxRackFullDependencies(testName string) string {
+	return fmt.Sprintf(`
+resource "netbox_tenant" "test" {
+  name = "%[1]s"
+}
+
+resource "netbox_site" "test" {
+  name = "%[1]s"
+  status = "active"
+}
+
+resource "netbox_rack_role" "test" {
+  name = "%[1]s"
+  color_hex = "123456"
+}
+
+resource "netbox_location" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+}
+
+resource "netbox_tag" "test" {
+  name = "%[1]s"
+}`, testName)
+}
+
+func TestAccNetboxRack_basic(t *testing.T) {
+
+	testSlug := "rack_basic"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNetboxRackFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_rack" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+  location_id = netbox_location.test.id
+  role_id = netbox_rack_role.test.id
+  tenant_id = netbox_tenant.test.id
+  status = "planned"
+  width = 23
+  u_height = 48
+  serial = "ABCDEF"
+  asset_tag = "%[1]s"
+  tags = [netbox_tag.test.name]
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_rack.test", "name", testName),
+					resource.TestCheckResourceAttrPair("netbox_rack.test", "site_id", "netbox_site.test", "id"),
+					resource.TestCheckResourceAttrPair("netbox_rack.test", "location_id", "netbox_location.test", "id"),
+					resource.TestCheckResourceAttrPair("netbox_rack.test", "role_id", "netbox_rack_role.test", "id"),
+					resource.TestCheckResourceAttrPair("netbox_rack.test", "tenant_id", "netbox_tenant.test", "id"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "status", "planned"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "width", "23"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "u_height", "48"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "serial", "ABCDEF"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "asset_tag", testName),
+					resource.TestCheckResourceAttr("netbox_rack.test", "tags.#", "1"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "tags.0", testName),
+				),
+			},
+			{
+				Config: testAccNetboxRackFullDependencies(testName) + fmt.Sprintf(`
+resource "netbox_rack" "test" {
+  name = "%[1]s"
+  site_id = netbox_site.test.id
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_rack.test", "name", testName),
+					resource.TestCheckResourceAttr("netbox_rack.test", "location_id", "0"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "role_id", "0"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "tenant_id", "0"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "asset_tag", ""),
+					resource.TestCheckResourceAttr("netbox_rack.test", "status", "active"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "width", "19"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "u_height", "42"),
+					resource.TestCheckResourceAttr("netbox_rack.test", "serial", ""),
+					resource.TestCheckResourceAttr("netbox_rack.test", "tags.#", "0"),
+				),
+			},
+			{
+				ResourceName:      "netbox_rack.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func init() {
+	resource.AddTestSweepers("netbox_rack", &resource.Sweeper{
+		Name:         "netbox_rack",
+		Dependencies: []string{"netbox_device"},
+		F: func(region string) error {
+			m, err := sharedClientForRegion(region)
+			if err != nil {
+				return fmt.Errorf("Error getting client: %s", err)
+			}
+			api := m.(*client.NetBoxAPI)
+			params := dcim.NewDcimRacksListParams()
+			res, err := api.Dcim.DcimRacksList(params, nil)
+			if err != nil {
+				return err
+			}
+			for _, rack := range res.GetPayload().Results {
+				if strings.HasPrefix(*rack.Name, testPrefix) {
+					deleteParams := dcim.NewDcimRacksDeleteParams().WithID(rack.ID)
+					_, err := api.Dcim.DcimRacksDelete(deleteParams, nil)
+					if err != nil {
+						return err
+					}
+					log.Print("[DEBUG] Deleted a rack")
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -1,21 +1,23 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/ipam"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxRir() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxRirCreate,
-		Read:   resourceNetboxRirRead,
-		Update: resourceNetboxRirUpdate,
-		Delete: resourceNetboxRirDelete,
+		CreateContext: resourceNetboxRirCreate,
+		ReadContext:   resourceNetboxRirRead,
+		UpdateContext: resourceNetboxRirUpdate,
+		DeleteContext: resourceNetboxRirDelete,
+		CustomizeDiff: customizeDiffCustomFieldsAll,
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -28,14 +30,16 @@ func resourceNetboxRir() *schema.Resource {
 				Computed:     true,
 				ValidateFunc: validation.StringLenBetween(1, 100),
 			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
 	}
 }
-func resourceNetboxRirCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxRirCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	data := models.RIR{}
 
 	name := d.Get("name").(string)
@@ -51,30 +55,35 @@ func resourceNetboxRirCreate(d *schema.ResourceData, m interface{}) error {
 	data.Name = &name
 	data.Slug = &slug
 
-	params := ipam.NewIpamRirsCreateParams().WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := ipam.NewIpamRirsCreateParamsWithContext(ctx).WithData(&data)
 	res, err := api.Ipam.IpamRirsCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxRir().Schema)
 	}
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxRirUpdate(d, m)
+	return resourceNetboxRirUpdate(ctx, d, m)
 }
 
-func resourceNetboxRirRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxRirRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := ipam.NewIpamRirsReadParams().WithID(id)
+	params := ipam.NewIpamRirsReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Ipam.IpamRirsRead(params, nil)
 	if err != nil {
-		errorcode := err.(*ipam.IpamRirsReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxRir().Schema)
 	}
 
 	if res.GetPayload().Name != nil {
@@ -85,11 +94,15 @@ func resourceNetboxRirRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("slug", res.GetPayload().Slug)
 	}
 
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
-func resourceNetboxRirUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxRirUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.RIR{}
 
@@ -106,21 +119,27 @@ func resourceNetboxRirUpdate(d *schema.ResourceData, m interface{}) error {
 	data.Name = &name
 	data.Slug = &slug
 
-	params := ipam.NewIpamRirsUpdateParams().WithID(id).WithData(&data)
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := ipam.NewIpamRirsUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 	_, err := api.Ipam.IpamRirsUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxRir().Schema)
 	}
-	return resourceNetboxRirRead(d, m)
+	return resourceNetboxRirRead(ctx, d, m)
 }
 
-func resourceNetboxRirDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxRirDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := ipam.NewIpamRirsDeleteParams().WithID(id)
+	params := ipam.NewIpamRirsDeleteParamsWithContext(ctx).WithID(id)
 	_, err := api.Ipam.IpamRirsDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxRir().Schema)
 	}
 	d.SetId("")
 	return nil
@@ -68,7 +68,7 @@ resource "netbox_service" "test" {
 
 func testAccCheckServiceDestroy(s *terraform.State) error {
 	// retrieve the connection established in Provider configuration
-	conn := testAccProvider.Meta().(*client.NetBoxAPI)
+	conn := testAccProvider.Meta().(*providerState)
 
 	// loop through the resources in state, verifying each service
 	// is destroyed
@@ -87,8 +87,7 @@ func testAccCheckServiceDestroy(s *terraform.State) error {
 		}
 
 		if err != nil {
-			errorcode := err.(*ipam.IpamServicesReadDefault).Code()
-			if errorcode == 404 {
+			if isNotFoundError(err) {
 				return nil
 			}
 			return err
@@ -1,21 +1,29 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/dcim"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxSite() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxSiteCreate,
-		Read:   resourceNetboxSiteRead,
-		Update: resourceNetboxSiteUpdate,
-		Delete: resourceNetboxSiteDelete,
+		CreateContext: resourceNetboxSiteCreate,
+		ReadContext:   resourceNetboxSiteRead,
+		UpdateContext: resourceNetboxSiteUpdate,
+		DeleteContext: resourceNetboxSiteDelete,
+		CustomizeDiff: customdiff.All(
+			customizeDiffTagsAll,
+			customizeDiffDefaultTenant,
+			requireNetboxCapability(capabilitySiteASN, "asn"),
+			customizeDiffCustomFieldsAll,
+		),
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -55,9 +63,14 @@ func resourceNetboxSite() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
+			"group_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
 			"tenant_id": &schema.Schema{
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 			},
 			"tags": &schema.Schema{
 				Type: schema.TypeSet,
@@ -67,6 +80,7 @@ func resourceNetboxSite() *schema.Resource {
 				Optional: true,
 				Set:      schema.HashString,
 			},
+			tagsAllKey: tagsAllSchema,
 			"timezone": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
@@ -75,16 +89,15 @@ func resourceNetboxSite() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
-			customFieldsKey: customFieldsSchema,
-		},
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
+		Importer: naturalKeyImporter("netbox_site", "a slug", lookupSitesBySlug),
 	}
 }
 
-func resourceNetboxSiteCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxSiteCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	data := models.WritableSite{}
 
@@ -124,6 +137,11 @@ func resourceNetboxSiteCreate(d *schema.ResourceData, m interface{}) error {
 		data.Region = int64ToPtr(int64(regionIDValue.(int)))
 	}
 
+	groupIDValue, ok := d.GetOk("group_id")
+	if ok {
+		data.Group = int64ToPtr(int64(groupIDValue.(int)))
+	}
+
 	tenantIDValue, ok := d.GetOk("tenant_id")
 	if ok {
 		data.Tenant = int64ToPtr(int64(tenantIDValue.(int)))
@@ -138,40 +156,44 @@ func resourceNetboxSiteCreate(d *schema.ResourceData, m interface{}) error {
 		data.Asn = int64ToPtr(int64(asnValue.(int)))
 	}
 
-	data.Tags, _ = getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
+	data.Tags = tags
 
-	ct, ok := d.GetOk(customFieldsKey)
-	if ok {
-		data.CustomFields = ct
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
 	}
+	data.CustomFields = customFields
 
-	params := dcim.NewDcimSitesCreateParams().WithData(&data)
+	params := dcim.NewDcimSitesCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Dcim.DcimSitesCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxSite().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxSiteRead(d, m)
+	return resourceNetboxSiteRead(ctx, d, m)
 }
 
-func resourceNetboxSiteRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxSiteRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := dcim.NewDcimSitesReadParams().WithID(id)
+	params := dcim.NewDcimSitesReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Dcim.DcimSitesRead(params, nil)
 
 	if err != nil {
-		errorcode := err.(*dcim.DcimSitesReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxSite().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
@@ -190,23 +212,28 @@ func resourceNetboxSiteRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("region_id", nil)
 	}
 
+	if res.GetPayload().Group != nil {
+		d.Set("group_id", res.GetPayload().Group.ID)
+	} else {
+		d.Set("group_id", nil)
+	}
+
 	if res.GetPayload().Tenant != nil {
 		d.Set("tenant_id", res.GetPayload().Tenant.ID)
 	} else {
 		d.Set("tenant_id", nil)
 	}
 
-	cf := getCustomFields(res.GetPayload().CustomFields)
-	if cf != nil {
-		d.Set(customFieldsKey, cf)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
 	}
-	d.Set("tags", getTagListFromNestedTagList(res.GetPayload().Tags))
+	setTagsFromNestedTagList(api, d, res.GetPayload().Tags)
 
 	return nil
 }
 
-func resourceNetboxSiteUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxSiteUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.WritableSite{}
@@ -224,10 +251,6 @@ func resourceNetboxSiteUpdate(d *schema.ResourceData, m interface{}) error {
 
 	data.Status = d.Get("status").(string)
 
-	if description, ok := d.GetOk("description"); ok {
-		data.Description = description.(string)
-	}
-
 	if facility, ok := d.GetOk("facility"); ok {
 		data.Facility = facility.(string)
 	}
@@ -247,6 +270,18 @@ func resourceNetboxSiteUpdate(d *schema.ResourceData, m interface{}) error {
 		data.Region = int64ToPtr(int64(regionIDValue.(int)))
 	}
 
+	// group is sent as null and description as empty if unset to remove
+	// them
+	fields := map[string]interface{}{
+		"group":       nil,
+		"description": d.Get("description").(string),
+	}
+
+	groupIDValue, ok := d.GetOk("group_id")
+	if ok {
+		fields["group"] = int64(groupIDValue.(int))
+	}
+
 	tenantIDValue, ok := d.GetOk("tenant_id")
 	if ok {
 		data.Tenant = int64ToPtr(int64(tenantIDValue.(int)))
@@ -261,32 +296,37 @@ func resourceNetboxSiteUpdate(d *schema.ResourceData, m interface{}) error {
 		data.Asn = int64ToPtr(int64(asnValue.(int)))
 	}
 
-	data.Tags, _ = getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
+	data.Tags = tags
 
-	cf, ok := d.GetOk(customFieldsKey)
-	if ok {
-		data.CustomFields = cf
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
 	}
+	data.CustomFields = customFields
 
-	params := dcim.NewDcimSitesPartialUpdateParams().WithID(id).WithData(&data)
+	params := dcim.NewDcimSitesPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
-	_, err := api.Dcim.DcimSitesPartialUpdate(params, nil)
+	_, err := api.Dcim.DcimSitesPartialUpdate(params, nil, withRequestFields(fields))
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxSite().Schema)
 	}
 
-	return resourceNetboxSiteRead(d, m)
+	return resourceNetboxSiteRead(ctx, d, m)
 }
 
-func resourceNetboxSiteDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxSiteDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := dcim.NewDcimSitesDeleteParams().WithID(id)
+	params := dcim.NewDcimSitesDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Dcim.DcimSitesDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxSite().Schema)
 	}
 	return nil
 }
@@ -0,0 +1,182 @@
+package netbox
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceNetboxSiteGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNetboxSiteGroupCreate,
+		ReadContext:   resourceNetboxSiteGroupRead,
+		UpdateContext: resourceNetboxSiteGroupUpdate,
+		DeleteContext: resourceNetboxSiteGroupDelete,
+		CustomizeDiff: customizeDiffCustomFieldsAll,
+
+		Description: `From the [official documentation](https://docs.netbox.dev/en/stable/core-functionality/sites-and-racks/#site-groups):
+
+> Like regions, site groups can be arranged in a recursive hierarchy for grouping sites. However, whereas regions are intended for geographic organization, site groups may be used for functional grouping. For example, you might classify sites as corporate, branch, or customer sites in addition to where they are physically located.`,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"slug": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringLenBetween(0, 100),
+			},
+			"parent_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"description": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringLenBetween(0, 200),
+			},
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
+		},
+		Importer: naturalKeyImporter("netbox_site_group", "a slug", lookupSiteGroupsBySlug),
+	}
+}
+
+func resourceNetboxSiteGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	data := models.WritableSiteGroup{}
+
+	name := d.Get("name").(string)
+	data.Name = &name
+
+	slugValue, slugOk := d.GetOk("slug")
+	// Default slug to name if not given
+	if !slugOk {
+		data.Slug = strToPtr(name)
+	} else {
+		data.Slug = strToPtr(slugValue.(string))
+	}
+
+	if description, ok := d.GetOk("description"); ok {
+		data.Description = description.(string)
+	}
+
+	parentIDValue, ok := d.GetOk("parent_id")
+	if ok {
+		data.Parent = int64ToPtr(int64(parentIDValue.(int)))
+	}
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := dcim.NewDcimSiteGroupsCreateParamsWithContext(ctx).WithData(&data)
+
+	res, err := api.Dcim.DcimSiteGroupsCreate(params, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxSiteGroup().Schema)
+	}
+	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
+
+	return resourceNetboxSiteGroupRead(ctx, d, m)
+}
+
+func resourceNetboxSiteGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimSiteGroupsReadParamsWithContext(ctx).WithID(id)
+
+	res, err := api.Dcim.DcimSiteGroupsRead(params, nil)
+
+	if err != nil {
+		if isNotFoundError(err) {
+			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
+			d.SetId("")
+			return nil
+		}
+		return diagFromNetboxError(err, resourceNetboxSiteGroup().Schema)
+	}
+
+	d.Set("name", res.GetPayload().Name)
+	d.Set("slug", res.GetPayload().Slug)
+	if res.GetPayload().Parent != nil {
+		d.Set("parent_id", res.GetPayload().Parent.ID)
+	} else {
+		d.Set("parent_id", nil)
+	}
+	d.Set("description", res.GetPayload().Description)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceNetboxSiteGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	data := models.WritableSiteGroup{}
+
+	name := d.Get("name").(string)
+	data.Name = &name
+
+	slugValue, slugOk := d.GetOk("slug")
+	// Default slug to name if not given
+	if !slugOk {
+		data.Slug = strToPtr(name)
+	} else {
+		data.Slug = strToPtr(slugValue.(string))
+	}
+
+	// parent is sent as null and description as empty if unset to remove
+	// them
+	fields := map[string]interface{}{
+		"parent":      nil,
+		"description": d.Get("description").(string),
+	}
+
+	parentIDValue, ok := d.GetOk("parent_id")
+	if ok {
+		fields["parent"] = int64(parentIDValue.(int))
+	}
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
+
+	params := dcim.NewDcimSiteGroupsPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
+
+	_, err := api.Dcim.DcimSiteGroupsPartialUpdate(params, nil, withRequestFields(fields))
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxSiteGroup().Schema)
+	}
+
+	return resourceNetboxSiteGroupRead(ctx, d, m)
+}
+
+func resourceNetboxSiteGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
+
+	id, _ := strconv.ParseInt(d.Id(), 10, 64)
+	params := dcim.NewDcimSiteGroupsDeleteParamsWithContext(ctx).WithID(id)
+
+	_, err := api.Dcim.DcimSiteGroupsDelete(params, nil)
+	if err != nil {
+		return diagFromNetboxError(err, resourceNetboxSiteGroup().Schema)
+	}
+	return nil
+}
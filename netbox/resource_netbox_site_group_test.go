@@ -0,0 +1,123 @@
+package netbox
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/dcim"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccNetboxSiteGroup_basic(t *testing.T) {
+
+	testSlug := "site_group_basic"
+	testName := testAccGetTestName(testSlug)
+	randomSlug := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "netbox_site_group" "parent" {
+  name = "%[1]s-parent"
+}
+
+resource "netbox_site_group" "test" {
+  name = "%[1]s"
+  slug = "%[2]s"
+  description = "%[1]s"
+  parent_id = netbox_site_group.parent.id
+}`, testName, randomSlug),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_site_group.test", "name", testName),
+					resource.TestCheckResourceAttr("netbox_site_group.test", "slug", randomSlug),
+					resource.TestCheckResourceAttrPair("netbox_site_group.test", "parent_id", "netbox_site_group.parent", "id"),
+					resource.TestCheckResourceAttr("netbox_site_group.test", "description", testName),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "netbox_site_group" "parent" {
+  name = "%[1]s-parent"
+}
+
+resource "netbox_site_group" "test" {
+  name = "%[1]s"
+  slug = "%[2]s"
+}`, testName, randomSlug),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_site_group.test", "parent_id", "0"),
+					resource.TestCheckResourceAttr("netbox_site_group.test", "description", ""),
+				),
+			},
+			{
+				ResourceName:      "netbox_site_group.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "netbox_site_group.test",
+				ImportState:       true,
+				ImportStateId:     randomSlug,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccNetboxSiteGroup_defaultSlug(t *testing.T) {
+
+	testSlug := "site_group_defSlug"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "netbox_site_group" "test" {
+  name = "%s"
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_site_group.test", "name", testName),
+					resource.TestCheckResourceAttr("netbox_site_group.test", "slug", testName),
+					resource.TestCheckResourceAttr("netbox_site_group.test", "parent_id", "0"),
+				),
+			},
+		},
+	})
+}
+
+func init() {
+	resource.AddTestSweepers("netbox_site_group", &resource.Sweeper{
+		Name:         "netbox_site_group",
+		Dependencies: []string{"netbox_site"},
+		F: func(region string) error {
+			m, err := sharedClientForRegion(region)
+			if err != nil {
+				return fmt.Errorf("Error getting client: %s", err)
+			}
+			api := m.(*client.NetBoxAPI)
+			params := dcim.NewDcimSiteGroupsListParams()
+			res, err := api.Dcim.DcimSiteGroupsList(params, nil)
+			if err != nil {
+				return err
+			}
+			for _, siteGroup := range res.GetPayload().Results {
+				if strings.HasPrefix(*siteGroup.Name, testPrefix) {
+					deleteParams := dcim.NewDcimSiteGroupsDeleteParams().WithID(siteGroup.ID)
+					_, err := api.Dcim.DcimSiteGroupsDelete(deleteParams, nil)
+					if err != nil && !isNotFoundError(err) {
+						return err
+					}
+					log.Print("[DEBUG] Deleted a site group")
+				}
+			}
+			return nil
+		},
+	})
+}
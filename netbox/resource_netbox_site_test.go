@@ -83,6 +83,55 @@ resource "netbox_site" "test" {
 	})
 }
 
+func TestAccNetboxSite_group(t *testing.T) {
+
+	testSlug := "site_group"
+	testName := testAccGetTestName(testSlug)
+	resource.ParallelTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "netbox_site_group" "test" {
+  name = "%[1]s"
+}
+
+resource "netbox_site" "test" {
+  name = "%[1]s"
+  status = "active"
+  description = "%[1]s"
+  group_id = netbox_site_group.test.id
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("netbox_site.test", "group_id", "netbox_site_group.test", "id"),
+					resource.TestCheckResourceAttr("netbox_site.test", "description", testName),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "netbox_site_group" "test" {
+  name = "%[1]s"
+}
+
+resource "netbox_site" "test" {
+  name = "%[1]s"
+  status = "active"
+}`, testName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("netbox_site.test", "group_id", "0"),
+					resource.TestCheckResourceAttr("netbox_site.test", "description", ""),
+				),
+			},
+			{
+				ResourceName:      "netbox_site.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccNetboxSite_customFields(t *testing.T) {
 	testSlug := "site_detail"
 	testName := testAccGetTestName(testSlug)
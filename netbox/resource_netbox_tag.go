@@ -1,22 +1,23 @@
 package netbox
 
 import (
+	"context"
 	"regexp"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/extras"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceNetboxTag() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxTagCreate,
-		Read:   resourceNetboxTagRead,
-		Update: resourceNetboxTagUpdate,
-		Delete: resourceNetboxTagDelete,
+		CreateContext: resourceNetboxTagCreate,
+		ReadContext:   resourceNetboxTagRead,
+		UpdateContext: resourceNetboxTagUpdate,
+		DeleteContext: resourceNetboxTagDelete,
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -47,14 +48,12 @@ func resourceNetboxTag() *schema.Resource {
 				Set:      schema.HashString,
 			},
 		},
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
-		},
+		Importer: naturalKeyImporter("netbox_tag", "a slug", lookupTagsBySlug),
 	}
 }
 
-func resourceNetboxTagCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxTagCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	name := d.Get("name").(string)
 
@@ -69,7 +68,7 @@ func resourceNetboxTagCreate(d *schema.ResourceData, m interface{}) error {
 
 	color := d.Get("color_hex").(string)
 	description := d.Get("description").(string)
-	params := extras.NewExtrasTagsCreateParams().WithData(
+	params := extras.NewExtrasTagsCreateParamsWithContext(ctx).WithData(
 		&models.Tag{
 			Name:        &name,
 			Slug:        &slug,
@@ -81,28 +80,29 @@ func resourceNetboxTagCreate(d *schema.ResourceData, m interface{}) error {
 	res, err := api.Extras.ExtrasTagsCreate(params, nil)
 	if err != nil {
 		//return errors.New(getTextFromError(err))
-		return err
+		return diagFromNetboxError(err, resourceNetboxTag().Schema)
 	}
 
+	api.tagCache.invalidate()
+
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxTagRead(d, m)
+	return resourceNetboxTagRead(ctx, d, m)
 }
 
-func resourceNetboxTagRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxTagRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := extras.NewExtrasTagsReadParams().WithID(id)
+	params := extras.NewExtrasTagsReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Extras.ExtrasTagsRead(params, nil)
 	if err != nil {
-		errorcode := err.(*extras.ExtrasTagsReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxTag().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
@@ -112,8 +112,8 @@ func resourceNetboxTagRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceNetboxTagUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxTagUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.Tag{}
@@ -136,25 +136,29 @@ func resourceNetboxTagUpdate(d *schema.ResourceData, m interface{}) error {
 	data.Color = color
 	data.Description = description
 
-	params := extras.NewExtrasTagsUpdateParams().WithID(id).WithData(&data)
+	params := extras.NewExtrasTagsUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Extras.ExtrasTagsUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxTag().Schema)
 	}
 
-	return resourceNetboxTagRead(d, m)
+	api.tagCache.invalidate()
+
+	return resourceNetboxTagRead(ctx, d, m)
 }
 
-func resourceNetboxTagDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxTagDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := extras.NewExtrasTagsDeleteParams().WithID(id)
+	params := extras.NewExtrasTagsDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Extras.ExtrasTagsDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxTag().Schema)
 	}
+
+	api.tagCache.invalidate()
 	return nil
 }
@@ -1,20 +1,21 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/users"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceNetboxUser() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxUserCreate,
-		Read:   resourceNetboxUserRead,
-		Update: resourceNetboxUserUpdate,
-		Delete: resourceNetboxUserDelete,
+		CreateContext: resourceNetboxUserCreate,
+		ReadContext:   resourceNetboxUserRead,
+		UpdateContext: resourceNetboxUserUpdate,
+		DeleteContext: resourceNetboxUserDelete,
 
 		Schema: map[string]*schema.Schema{
 			"username": &schema.Schema{
@@ -42,8 +43,8 @@ func resourceNetboxUser() *schema.Resource {
 		},
 	}
 }
-func resourceNetboxUserCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	data := models.WritableUser{}
 
 	username := d.Get("username").(string)
@@ -58,30 +59,29 @@ func resourceNetboxUserCreate(d *schema.ResourceData, m interface{}) error {
 
 	data.Groups = []int64{}
 
-	params := users.NewUsersUsersCreateParams().WithData(&data)
+	params := users.NewUsersUsersCreateParamsWithContext(ctx).WithData(&data)
 	res, err := api.Users.UsersUsersCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxUser().Schema)
 	}
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxUserRead(d, m)
+	return resourceNetboxUserRead(ctx, d, m)
 }
 
-func resourceNetboxUserRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := users.NewUsersUsersReadParams().WithID(id)
+	params := users.NewUsersUsersReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Users.UsersUsersRead(params, nil)
 	if err != nil {
-		errorcode := err.(*users.UsersUsersReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxUser().Schema)
 	}
 
 	if res.GetPayload().Username != nil {
@@ -96,8 +96,8 @@ func resourceNetboxUserRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceNetboxUserUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.WritableUser{}
 
@@ -113,21 +113,21 @@ func resourceNetboxUserUpdate(d *schema.ResourceData, m interface{}) error {
 
 	data.Groups = []int64{}
 
-	params := users.NewUsersUsersUpdateParams().WithID(id).WithData(&data)
+	params := users.NewUsersUsersUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 	_, err := api.Users.UsersUsersUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxUser().Schema)
 	}
-	return resourceNetboxUserRead(d, m)
+	return resourceNetboxUserRead(ctx, d, m)
 }
 
-func resourceNetboxUserDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := users.NewUsersUsersDeleteParams().WithID(id)
+	params := users.NewUsersUsersDeleteParamsWithContext(ctx).WithID(id)
 	_, err := api.Users.UsersUsersDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxUser().Schema)
 	}
 	d.SetId("")
 	return nil
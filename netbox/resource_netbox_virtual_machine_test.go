@@ -174,7 +174,7 @@ resource "netbox_virtual_machine" "test" {
 
 func testAccCheckVirtualMachineDestroy(s *terraform.State) error {
 	// retrieve the connection established in Provider configuration
-	conn := testAccProvider.Meta().(*client.NetBoxAPI)
+	conn := testAccProvider.Meta().(*providerState)
 
 	// loop through the resources in state, verifying each virtual machine
 	// is destroyed
@@ -193,8 +193,7 @@ func testAccCheckVirtualMachineDestroy(s *terraform.State) error {
 		}
 
 		if err != nil {
-			errorcode := err.(*virtualization.VirtualizationVirtualMachinesReadDefault).Code()
-			if errorcode == 404 {
+			if isNotFoundError(err) {
 				return nil
 			}
 			return err
@@ -1,20 +1,23 @@
 package netbox
 
 import (
+	"context"
 	"strconv"
 
-	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/ipam"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceNetboxVrf() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceNetboxVrfCreate,
-		Read:   resourceNetboxVrfRead,
-		Update: resourceNetboxVrfUpdate,
-		Delete: resourceNetboxVrfDelete,
+		CreateContext: resourceNetboxVrfCreate,
+		ReadContext:   resourceNetboxVrfRead,
+		UpdateContext: resourceNetboxVrfUpdate,
+		DeleteContext: resourceNetboxVrfDelete,
+		CustomizeDiff: customdiff.All(customizeDiffTagsAll, customizeDiffDefaultTenant, customizeDiffCustomFieldsAll),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -24,6 +27,7 @@ func resourceNetboxVrf() *schema.Resource {
 			"tenant_id": {
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 			},
 			"tags": {
 				Type: schema.TypeSet,
@@ -33,6 +37,9 @@ func resourceNetboxVrf() *schema.Resource {
 				Optional: true,
 				Set:      schema.HashString,
 			},
+			tagsAllKey:         tagsAllSchema,
+			customFieldsKey:    customFieldsSchema,
+			customFieldsAllKey: customFieldsAllSchema,
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -40,8 +47,8 @@ func resourceNetboxVrf() *schema.Resource {
 	}
 }
 
-func resourceNetboxVrfCreate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxVrfCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	data := models.WritableVRF{}
 
 	name := d.Get("name").(string)
@@ -52,37 +59,46 @@ func resourceNetboxVrfCreate(d *schema.ResourceData, m interface{}) error {
 		data.Tenant = &tenant_id
 	}
 
-	data.Tags, _ = getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
+	data.Tags = tags
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
 
 	data.ExportTargets = []int64{}
 	data.ImportTargets = []int64{}
 
-	params := ipam.NewIpamVrfsCreateParams().WithData(&data)
+	params := ipam.NewIpamVrfsCreateParamsWithContext(ctx).WithData(&data)
 
 	res, err := api.Ipam.IpamVrfsCreate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxVrf().Schema)
 	}
 
 	d.SetId(strconv.FormatInt(res.GetPayload().ID, 10))
 
-	return resourceNetboxVrfRead(d, m)
+	return resourceNetboxVrfRead(ctx, d, m)
 }
 
-func resourceNetboxVrfRead(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxVrfRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := ipam.NewIpamVrfsReadParams().WithID(id)
+	params := ipam.NewIpamVrfsReadParamsWithContext(ctx).WithID(id)
 
 	res, err := api.Ipam.IpamVrfsRead(params, nil)
 	if err != nil {
-		errorcode := err.(*ipam.IpamVrfsReadDefault).Code()
-		if errorcode == 404 {
+		if isNotFoundError(err) {
 			// If the ID is updated to blank, this tells Terraform the resource no longer exists (maybe it was destroyed out of band). Just like the destroy callback, the Read function should gracefully handle this case. https://www.terraform.io/docs/extend/writing-custom-providers.html
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diagFromNetboxError(err, resourceNetboxVrf().Schema)
 	}
 
 	d.Set("name", res.GetPayload().Name)
@@ -91,46 +107,59 @@ func resourceNetboxVrfRead(d *schema.ResourceData, m interface{}) error {
 	} else {
 		d.Set("tenant_id", nil)
 	}
+	setTagsFromNestedTagList(api, d, res.GetPayload().Tags)
+	if err := setCustomFieldsFromAPI(api, d, res.GetPayload().CustomFields); err != nil {
+		return diag.FromErr(err)
+	}
 	return nil
 }
 
-func resourceNetboxVrfUpdate(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxVrfUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
 	data := models.WritableVRF{}
 
 	name := d.Get("name").(string)
 
-	tags, _ := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	tags, diags := getNestedTagListFromResourceDataSet(api, d.Get("tags"))
+	if diags.HasError() {
+		return diags
+	}
 
 	data.Name = &name
 	data.Tags = tags
+
+	customFields, diags := getCustomFieldsFromResourceData(api, d)
+	if diags.HasError() {
+		return diags
+	}
+	data.CustomFields = customFields
 	data.ExportTargets = []int64{}
 	data.ImportTargets = []int64{}
 
 	if tenantID, ok := d.GetOk("tenant_id"); ok {
 		data.Tenant = int64ToPtr(int64(tenantID.(int)))
 	}
-	params := ipam.NewIpamVrfsPartialUpdateParams().WithID(id).WithData(&data)
+	params := ipam.NewIpamVrfsPartialUpdateParamsWithContext(ctx).WithID(id).WithData(&data)
 
 	_, err := api.Ipam.IpamVrfsPartialUpdate(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxVrf().Schema)
 	}
 
-	return resourceNetboxVrfRead(d, m)
+	return resourceNetboxVrfRead(ctx, d, m)
 }
 
-func resourceNetboxVrfDelete(d *schema.ResourceData, m interface{}) error {
-	api := m.(*client.NetBoxAPI)
+func resourceNetboxVrfDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	api := m.(*providerState)
 
 	id, _ := strconv.ParseInt(d.Id(), 10, 64)
-	params := ipam.NewIpamVrfsDeleteParams().WithID(id)
+	params := ipam.NewIpamVrfsDeleteParamsWithContext(ctx).WithID(id)
 
 	_, err := api.Ipam.IpamVrfsDelete(params, nil)
 	if err != nil {
-		return err
+		return diagFromNetboxError(err, resourceNetboxVrf().Schema)
 	}
 	return nil
 }
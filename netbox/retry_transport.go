@@ -0,0 +1,158 @@
+package netbox
+
+import (
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// retryTransport is a transport that retries requests failing with a
+// transient error (connection errors, 429, 502, 503 and 504) using
+// exponential backoff with jitter.
+//
+// Requests with a non-idempotent method (POST, PATCH) are only retried on 429
+// responses, as in that case Netbox did not process the request. Retrying them
+// on other errors could create duplicate objects.
+type retryTransport struct {
+	original   http.RoundTripper
+	maxRetries int
+	waitMin    time.Duration
+	waitMax    time.Duration
+}
+
+// RoundTrip sends the request and retries it as long as the error is
+// considered transient and the retry budget is not exhausted.
+func (t retryTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// A request body that cannot be rewound can only be sent once
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return t.original.RoundTrip(r)
+	}
+
+	for attempt := 0; ; attempt++ {
+		req := r
+		if attempt > 0 {
+			req = r.Clone(r.Context())
+			if r.GetBody != nil {
+				body, err := r.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = body
+			}
+		}
+
+		resp, err := t.original.RoundTrip(req)
+
+		if attempt >= t.maxRetries || !shouldRetry(r, resp, err) {
+			return resp, err
+		}
+
+		wait := retryBackoff(attempt, t.waitMin, t.waitMax, resp)
+
+		log.WithFields(log.Fields{
+			"method":  r.Method,
+			"url":     r.URL.String(),
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}).Debug("Retrying request to Netbox after transient error")
+
+		if resp != nil {
+			// Drain the body so the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return nil, r.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isIdempotentMethod returns true if sending a request with the given method
+// twice has the same effect as sending it once.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry decides whether a request should be sent again, based on its
+// method and the outcome of the previous attempt.
+func shouldRetry(r *http.Request, resp *http.Response, err error) bool {
+	if r.Context().Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotentMethod(r.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotentMethod(r.Method)
+	}
+	return false
+}
+
+// retryBackoff returns how long to wait before the next attempt. A valid
+// Retry-After header on the response takes precedence over the exponential
+// backoff, but is capped at waitMax as well.
+func retryBackoff(attempt int, waitMin, waitMax time.Duration, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if wait > waitMax {
+				wait = waitMax
+			}
+			return wait
+		}
+	}
+
+	wait := time.Duration(float64(waitMin) * math.Pow(2, float64(attempt)))
+	if wait <= 0 || wait > waitMax {
+		wait = waitMax
+	}
+
+	// Add jitter so that parallel requests do not retry in lockstep
+	if half := int64(wait / 2); half > 0 {
+		wait = time.Duration(half + rand.Int63n(half))
+	}
+	return wait
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
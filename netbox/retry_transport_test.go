@@ -0,0 +1,174 @@
+package netbox
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRetryClient(maxRetries int) *http.Client {
+	return &http.Client{
+		Transport: retryTransport{
+			original:   http.DefaultTransport,
+			maxRetries: maxRetries,
+			waitMin:    time.Millisecond,
+			waitMax:    10 * time.Millisecond,
+		},
+	}
+}
+
+func TestRetryTransportRetriesTransientErrors(t *testing.T) {
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	resp, err := newTestRetryClient(3).Get(ts.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRetryTransportSendsCustomHeadersOnce(t *testing.T) {
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{"team-a"}, r.Header.Values("X-Team"))
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := &http.Client{
+		Transport: retryTransport{
+			original: customHeaderTransport{
+				original: http.DefaultTransport,
+				headers:  map[string]interface{}{"X-Team": "team-a"},
+			},
+			maxRetries: 3,
+			waitMin:    time.Millisecond,
+			waitMax:    10 * time.Millisecond,
+		},
+	}
+
+	resp, err := client.Get(ts.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRetryTransportGivesUpAfterMaxRetries(t *testing.T) {
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	resp, err := newTestRetryClient(2).Get(ts.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRetryTransportDoesNotRetryPostOnServerError(t *testing.T) {
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer ts.Close()
+
+	resp, err := newTestRetryClient(3).Post(ts.URL, "application/json", strings.NewReader(`{"name":"foo"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestRetryTransportRetriesPostOnTooManyRequests(t *testing.T) {
+
+	var calls int32
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	resp, err := newTestRetryClient(3).Post(ts.URL, "application/json", strings.NewReader(`{"name":"foo"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, []string{`{"name":"foo"}`, `{"name":"foo"}`}, bodies)
+}
+
+func TestRetryTransportDoesNotRetryClientErrors(t *testing.T) {
+
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	resp, err := newTestRetryClient(3).Get(ts.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestRetryBackoff(t *testing.T) {
+
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := retryBackoff(attempt, time.Second, 30*time.Second, nil)
+		assert.True(t, wait <= 30*time.Second)
+		assert.True(t, wait > 0)
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "7")
+	assert.Equal(t, 7*time.Second, retryBackoff(0, time.Second, 30*time.Second, resp))
+
+	// A Retry-After longer than the maximum wait is capped
+	resp.Header.Set("Retry-After", "3600")
+	assert.Equal(t, 30*time.Second, retryBackoff(0, time.Second, 30*time.Second, resp))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+
+	wait, ok := parseRetryAfter("120")
+	assert.True(t, ok)
+	assert.Equal(t, 120*time.Second, wait)
+
+	wait, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), wait)
+
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
@@ -1,53 +1,294 @@
 package netbox
 
 import (
+	"context"
 	"fmt"
+	"sync"
 
 	"github.com/fbreckle/go-netbox/netbox/client"
 	"github.com/fbreckle/go-netbox/netbox/client/extras"
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-func getNestedTagListFromResourceDataSet(client *client.NetBoxAPI, d interface{}) ([]*models.NestedTag, diag.Diagnostics) {
+// tagDefaultColor is the color of tags created by auto_create_tags. It matches
+// the default color of the netbox_tag resource.
+const tagDefaultColor = "9e9e9e"
+
+const tagsAllKey = "tags_all"
+
+var tagsAllSchema = &schema.Schema{
+	Type: schema.TypeSet,
+	Elem: &schema.Schema{
+		Type: schema.TypeString,
+	},
+	Computed:    true,
+	Set:         schema.HashString,
+	Description: "All tags of the object, including the `default_tags` of the provider.",
+}
+
+// tagCache holds all tags known to Netbox so that resolving the tags of a
+// resource does not require one API call per tag. The cache is loaded lazily
+// on first use and is safe for concurrent use.
+type tagCache struct {
+	api *client.NetBoxAPI
+
+	mu     sync.Mutex
+	loaded bool
+	byName map[string]*models.NestedTag
+	bySlug map[string]*models.NestedTag
+}
+
+func newTagCache(api *client.NetBoxAPI) *tagCache {
+	return &tagCache{
+		api: api,
+	}
+}
+
+// invalidate drops all cached tags. The next lookup reloads them from Netbox.
+func (c *tagCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.loaded = false
+	c.byName = nil
+	c.bySlug = nil
+}
+
+// load fetches all tags from Netbox, following the pagination until every
+// tag has been retrieved. The caller must hold the lock.
+func (c *tagCache) load() error {
+	byName := make(map[string]*models.NestedTag)
+	bySlug := make(map[string]*models.NestedTag)
+
+	tags, err := listAll(func(limit int64, offset int64) (listPage[*models.Tag], error) {
+		params := extras.NewExtrasTagsListParams()
+		params.Limit = &limit
+		params.Offset = &offset
+
+		res, err := c.api.Extras.ExtrasTagsList(params, nil)
+		if err != nil {
+			return listPage[*models.Tag]{}, err
+		}
+		payload := res.GetPayload()
+		return listPage[*models.Tag]{
+			results: payload.Results,
+			count:   payload.Count,
+			hasNext: payload.Next != nil,
+		}, nil
+	}, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		nestedTag := &models.NestedTag{
+			ID:   tag.ID,
+			Name: tag.Name,
+			Slug: tag.Slug,
+		}
+		byName[*tag.Name] = nestedTag
+		bySlug[*tag.Slug] = nestedTag
+	}
+
+	c.byName = byName
+	c.bySlug = bySlug
+	c.loaded = true
+	return nil
+}
+
+// lookup resolves a tag by its name or, if no tag has that name, by its slug.
+// A miss reloads the cache once, in case the tag was created after the cache
+// was loaded.
+func (c *tagCache) lookup(nameOrSlug string) (*models.NestedTag, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lookupLocked(nameOrSlug)
+}
+
+// lookupCached resolves a tag like lookup does, but never reloads the cache
+// on a miss. The cache is only loaded if it has not been loaded yet. It is
+// used while planning, where unknown tags are common and a reload per tag
+// would fetch all tags of Netbox again and again.
+func (c *tagCache) lookupCached(nameOrSlug string) (*models.NestedTag, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded {
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	if tag, ok := c.byName[nameOrSlug]; ok {
+		return tag, nil
+	}
+	return c.bySlug[nameOrSlug], nil
+}
+
+// lookupOrCreate resolves a tag like lookup does and creates it in Netbox if
+// it does not exist yet.
+func (c *tagCache) lookupOrCreate(name string) (*models.NestedTag, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tag, err := c.lookupLocked(name)
+	if err != nil || tag != nil {
+		return tag, err
+	}
+
+	slug := slugify(name)
+	params := extras.NewExtrasTagsCreateParams().WithData(
+		&models.Tag{
+			Name:  &name,
+			Slug:  &slug,
+			Color: tagDefaultColor,
+		},
+	)
+
+	res, err := c.api.Extras.ExtrasTagsCreate(params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := res.GetPayload()
+	tag = &models.NestedTag{
+		ID:   payload.ID,
+		Name: payload.Name,
+		Slug: payload.Slug,
+	}
+	c.byName[*tag.Name] = tag
+	c.bySlug[*tag.Slug] = tag
+	return tag, nil
+}
+
+// lookupLocked implements lookup. The caller must hold the lock.
+func (c *tagCache) lookupLocked(nameOrSlug string) (*models.NestedTag, error) {
+	reloaded := false
+	if !c.loaded {
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+		reloaded = true
+	}
+
+	for {
+		if tag, ok := c.byName[nameOrSlug]; ok {
+			return tag, nil
+		}
+		if tag, ok := c.bySlug[nameOrSlug]; ok {
+			return tag, nil
+		}
+		if reloaded {
+			return nil, nil
+		}
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+		reloaded = true
+	}
+}
+
+// resolveTag looks up a tag in the tag cache, creating it if auto_create_tags
+// is enabled.
+func resolveTag(api *providerState, name string) (*models.NestedTag, error) {
+	if api.autoCreateTags {
+		return api.tagCache.lookupOrCreate(name)
+	}
+	return api.tagCache.lookup(name)
+}
+
+// getNestedTagListFromResourceDataSet resolves the tags of a resource, merged
+// with the default tags of the provider.
+func getNestedTagListFromResourceDataSet(api *providerState, d interface{}) ([]*models.NestedTag, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	tagList := d.(*schema.Set).List()
+	tagList := append(d.(*schema.Set).List(), api.defaultTags...)
 	tags := []*models.NestedTag{}
+	seen := make(map[string]bool)
 	for _, tag := range tagList {
 
 		tagString := tag.(string)
-		params := extras.NewExtrasTagsListParams()
-		params.Name = &tagString
-		limit := int64(2) // We search for a unique tag. Having two hits suffices to know its not unique.
-		params.Limit = &limit
-		res, err := client.Extras.ExtrasTagsList(params, nil)
+		nestedTag, err := resolveTag(api, tagString)
+
 		if err != nil {
 			diags = append(diags, diag.Diagnostic{
-				Severity: diag.Warning,
-				Summary:  fmt.Sprintf("Error retrieving tag %s from netbox", tag.(string)),
-				Detail:   fmt.Sprintf("API Error trying to retrieve tag %s from netbox", tag.(string)),
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Error retrieving tag %s from netbox", tagString),
+				Detail:        fmt.Sprintf("API Error trying to retrieve tag %s from netbox: %v", tagString, err),
+				AttributePath: cty.GetAttrPath("tags"),
+			})
+		} else if nestedTag == nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Tag %s not found in netbox", tagString),
+				Detail:        fmt.Sprintf("Could not map tag %s to a tag name or slug in netbox. Create the tag first, e.g. with the netbox_tag resource, or set auto_create_tags in the provider configuration.", tagString),
+				AttributePath: cty.GetAttrPath("tags"),
+			})
+		} else if !seen[*nestedTag.Slug] {
+			// A tag given both in the resource and in the default tags is only sent once
+			seen[*nestedTag.Slug] = true
+			tags = append(tags, &models.NestedTag{
+				Name: nestedTag.Name,
+				Slug: nestedTag.Slug,
 			})
-		} else {
-			payload := res.GetPayload()
-			if *payload.Count == int64(1) {
-				tags = append(tags, &models.NestedTag{
-					Name: payload.Results[0].Name,
-					Slug: payload.Results[0].Slug,
-				})
-			} else {
-				diags = append(diags, diag.Diagnostic{
-					Severity: diag.Warning,
-					Summary:  fmt.Sprintf("Error retrieving tag %s from netbox", tag.(string)),
-					Detail:   fmt.Sprintf("Could not map tag %s to unique tag in netbox", tag.(string)),
-				})
-			}
 		}
 	}
 	return tags, diags
 }
 
+// setTagsFromNestedTagList sets the tags and tags_all attributes of a
+// resource from the tags returned by Netbox. Default tags of the provider are
+// left out of tags unless they are configured on the resource explicitly, so
+// that they do not show up as a diff.
+func setTagsFromNestedTagList(api *providerState, d *schema.ResourceData, nestedTags []*models.NestedTag) {
+	defaultTags := schema.NewSet(schema.HashString, api.defaultTags)
+	configured := d.Get("tags").(*schema.Set)
+
+	tags := []*models.NestedTag{}
+	for _, nestedTag := range nestedTags {
+		isDefault := defaultTags.Contains(*nestedTag.Name) || defaultTags.Contains(*nestedTag.Slug)
+		isConfigured := configured.Contains(*nestedTag.Name) || configured.Contains(*nestedTag.Slug)
+		if !isDefault || isConfigured {
+			tags = append(tags, nestedTag)
+		}
+	}
+
+	d.Set("tags", getTagListMatchingResourceData(d, tags))
+	d.Set(tagsAllKey, getTagListFromNestedTagList(nestedTags))
+}
+
+// customizeDiffTagsAll plans the value of tags_all, so that a change of the
+// default tags of the provider results in an update of the resource.
+func customizeDiffTagsAll(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	api := m.(*providerState)
+
+	if !d.NewValueKnown("tags") {
+		return d.SetNewComputed(tagsAllKey)
+	}
+
+	tagList := append(d.Get("tags").(*schema.Set).List(), api.defaultTags...)
+	tagsAll := schema.NewSet(schema.HashString, nil)
+	for _, tag := range tagList {
+		tagString := tag.(string)
+		// Tags are shown by name. Unknown tags are kept as they are, creating
+		// or rejecting them is up to create and update.
+		if nestedTag, err := api.tagCache.lookupCached(tagString); err == nil && nestedTag != nil {
+			tagsAll.Add(*nestedTag.Name)
+		} else {
+			tagsAll.Add(tagString)
+		}
+	}
+
+	if !tagsAll.Equal(d.Get(tagsAllKey)) {
+		return d.SetNew(tagsAllKey, tagsAll)
+	}
+	return nil
+}
+
 func getTagListFromNestedTagList(nestedTags []*models.NestedTag) []string {
 	tags := []string{}
 	for _, nestedTag := range nestedTags {
@@ -55,3 +296,20 @@ func getTagListFromNestedTagList(nestedTags []*models.NestedTag) []string {
 	}
 	return tags
 }
+
+// getTagListMatchingResourceData returns the names of the given tags, except
+// for tags that are referenced by their slug in the tags attribute of the
+// resource. Those keep their slug so that they do not show up as a diff.
+func getTagListMatchingResourceData(d *schema.ResourceData, nestedTags []*models.NestedTag) []string {
+	configured := d.Get("tags").(*schema.Set)
+
+	tags := []string{}
+	for _, nestedTag := range nestedTags {
+		if !configured.Contains(*nestedTag.Name) && configured.Contains(*nestedTag.Slug) {
+			tags = append(tags, *nestedTag.Slug)
+		} else {
+			tags = append(tags, *nestedTag.Name)
+		}
+	}
+	return tags
+}
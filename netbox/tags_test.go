@@ -1,9 +1,16 @@
 package netbox
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
 	"testing"
 
 	"github.com/fbreckle/go-netbox/netbox/models"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,3 +34,196 @@ func TestGetTagListFromNestedTagList(t *testing.T) {
 	}
 	assert.Equal(t, flat, expected)
 }
+
+// testTagHandler serves the tags of a fake Netbox and counts the requests in
+// calls.
+func testTagHandler(t *testing.T, calls *int32) http.HandlerFunc {
+	tags := []map[string]interface{}{
+		{"id": 1, "name": "Foo", "slug": "foo"},
+		{"id": 2, "name": "Bar", "slug": "bar"},
+		{"id": 3, "name": "Baz", "slug": "baz-slug"},
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		assert.Equal(t, "/api/extras/tags/", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPost {
+			var tag map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&tag)
+			tag["id"] = len(tags) + 1
+			tags = append(tags, tag)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(tag)
+			return
+		}
+
+		// Serve pages of two tags to exercise the pagination
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := offset + 2
+		if end > len(tags) {
+			end = len(tags)
+		}
+		var next interface{}
+		if end < len(tags) {
+			next = fmt.Sprintf("http://%s/api/extras/tags/?limit=2&offset=%d", r.Host, end)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count":   len(tags),
+			"next":    next,
+			"results": tags[offset:end],
+		})
+	}
+}
+
+func TestTagCacheLookup(t *testing.T) {
+
+	var calls int32
+	state := newTestProviderState(t, testTagHandler(t, &calls))
+	cache := newTagCache(state.NetBoxAPI)
+
+	tag, err := cache.lookup("Foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", *tag.Slug)
+	loadCalls := atomic.LoadInt32(&calls)
+	assert.Equal(t, int32(2), loadCalls)
+
+	tag, err = cache.lookup("baz-slug")
+	assert.NoError(t, err)
+	assert.Equal(t, "Baz", *tag.Name)
+	assert.Equal(t, loadCalls, atomic.LoadInt32(&calls))
+
+	// A miss reloads the cache once
+	tag, err = cache.lookup("Missing")
+	assert.NoError(t, err)
+	assert.Nil(t, tag)
+	assert.Equal(t, 2*loadCalls, atomic.LoadInt32(&calls))
+
+	cache.invalidate()
+	tag, err = cache.lookup("Bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", *tag.Slug)
+	assert.Equal(t, 3*loadCalls, atomic.LoadInt32(&calls))
+}
+
+func TestTagCacheLookupCached(t *testing.T) {
+
+	var calls int32
+	state := newTestProviderState(t, testTagHandler(t, &calls))
+	cache := newTagCache(state.NetBoxAPI)
+
+	tag, err := cache.lookupCached("baz-slug")
+	assert.NoError(t, err)
+	assert.Equal(t, "Baz", *tag.Name)
+	loadCalls := atomic.LoadInt32(&calls)
+	assert.Equal(t, int32(2), loadCalls)
+
+	// A miss does not reload the cache
+	for i := 0; i < 3; i++ {
+		tag, err = cache.lookupCached("Missing")
+		assert.NoError(t, err)
+		assert.Nil(t, tag)
+	}
+	assert.Equal(t, loadCalls, atomic.LoadInt32(&calls))
+}
+
+func TestGetTagListMatchingResourceData(t *testing.T) {
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxSite().Schema, map[string]interface{}{
+		"tags": []interface{}{"Foo", "bar"},
+	})
+
+	tags := []*models.NestedTag{
+		&models.NestedTag{
+			Name: strToPtr("Foo"),
+			Slug: strToPtr("foo"),
+		},
+		&models.NestedTag{
+			Name: strToPtr("Bar"),
+			Slug: strToPtr("bar"),
+		},
+		&models.NestedTag{
+			Name: strToPtr("Baz"),
+			Slug: strToPtr("baz"),
+		},
+	}
+
+	flat := getTagListMatchingResourceData(d, tags)
+	expected := []string{
+		"Foo",
+		"bar",
+		"Baz",
+	}
+	assert.Equal(t, expected, flat)
+}
+
+func TestGetNestedTagListFromResourceDataSet(t *testing.T) {
+
+	var calls int32
+	state := newTestProviderState(t, testTagHandler(t, &calls))
+
+	tags, diags := getNestedTagListFromResourceDataSet(state, schema.NewSet(schema.HashString, []interface{}{"Foo", "baz-slug"}))
+	assert.False(t, diags.HasError())
+	assert.Len(t, tags, 2)
+
+	_, diags = getNestedTagListFromResourceDataSet(state, schema.NewSet(schema.HashString, []interface{}{"Foo", "prod"}))
+	assert.True(t, diags.HasError())
+	assert.Len(t, diags, 1)
+	assert.Equal(t, cty.GetAttrPath("tags"), diags[0].AttributePath)
+
+	state.autoCreateTags = true
+	tags, diags = getNestedTagListFromResourceDataSet(state, schema.NewSet(schema.HashString, []interface{}{"Managed by Terraform"}))
+	assert.False(t, diags.HasError())
+	assert.Len(t, tags, 1)
+	assert.Equal(t, "managed-by-terraform", *tags[0].Slug)
+
+	// The created tag is resolved from the cache afterwards
+	before := atomic.LoadInt32(&calls)
+	tag, err := state.tagCache.lookup("managed-by-terraform")
+	assert.NoError(t, err)
+	assert.Equal(t, "Managed by Terraform", *tag.Name)
+	assert.Equal(t, before, atomic.LoadInt32(&calls))
+}
+
+func TestGetNestedTagListFromResourceDataSetWithDefaultTags(t *testing.T) {
+
+	var calls int32
+	state := newTestProviderState(t, testTagHandler(t, &calls))
+	state.defaultTags = []interface{}{"Bar", "foo"}
+
+	tags, diags := getNestedTagListFromResourceDataSet(state, schema.NewSet(schema.HashString, []interface{}{"Foo"}))
+	assert.False(t, diags.HasError())
+	assert.ElementsMatch(t, []string{"Foo", "Bar"}, getTagListFromNestedTagList(tags))
+}
+
+func TestSetTagsFromNestedTagList(t *testing.T) {
+
+	state := &providerState{
+		defaultTags: []interface{}{"managed-by-terraform", "Team"},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceNetboxSite().Schema, map[string]interface{}{
+		"tags": []interface{}{"Foo", "Team"},
+	})
+
+	setTagsFromNestedTagList(state, d, []*models.NestedTag{
+		&models.NestedTag{
+			Name: strToPtr("Foo"),
+			Slug: strToPtr("foo"),
+		},
+		&models.NestedTag{
+			Name: strToPtr("Managed by Terraform"),
+			Slug: strToPtr("managed-by-terraform"),
+		},
+		&models.NestedTag{
+			Name: strToPtr("Team"),
+			Slug: strToPtr("team"),
+		},
+	})
+
+	assert.ElementsMatch(t, []interface{}{"Foo", "Team"}, d.Get("tags").(*schema.Set).List())
+	assert.ElementsMatch(t, []interface{}{"Foo", "Managed by Terraform", "Team"}, d.Get(tagsAllKey).(*schema.Set).List())
+}
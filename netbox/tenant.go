@@ -0,0 +1,54 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/fbreckle/go-netbox/netbox/client"
+	"github.com/fbreckle/go-netbox/netbox/client/tenancy"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// getTenantIDFromIDOrSlug resolves a tenant given either by its numeric ID or
+// by its slug.
+func getTenantIDFromIDOrSlug(api *client.NetBoxAPI, idOrSlug string) (int64, error) {
+	if id, err := strconv.ParseInt(idOrSlug, 10, 64); err == nil {
+		return id, nil
+	}
+
+	params := tenancy.NewTenancyTenantsListParams()
+	params.Slug = &idOrSlug
+	limit := int64(2) // We search for a unique tenant. Having two hits suffices to know its not unique.
+	params.Limit = &limit
+
+	res, err := api.Tenancy.TenancyTenantsList(params, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	payload := res.GetPayload()
+	if *payload.Count != int64(1) {
+		return 0, fmt.Errorf("Could not map %s to a unique tenant in netbox", idOrSlug)
+	}
+	return payload.Results[0].ID, nil
+}
+
+// customizeDiffDefaultTenant plans the default tenant of the provider as the
+// tenant_id of resources that do not set tenant_id themselves. The attribute
+// therefore has to be computed, so that the default tenant does not show up as
+// a diff.
+func customizeDiffDefaultTenant(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	api := m.(*providerState)
+
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.GetAttr("tenant_id").IsNull() {
+		return nil
+	}
+
+	// Without a default tenant, omitting tenant_id removes the tenant
+	if d.Get("tenant_id").(int) != int(api.defaultTenantID) {
+		return d.SetNew("tenant_id", int(api.defaultTenantID))
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package netbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTenantIDFromIDOrSlug(t *testing.T) {
+
+	state := newTestProviderState(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/tenancy/tenants/", r.URL.Path)
+
+		results := []map[string]interface{}{}
+		if r.URL.Query().Get("slug") == "team-a" {
+			results = append(results, map[string]interface{}{"id": 7, "name": "Team A", "slug": "team-a"})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count":   len(results),
+			"results": results,
+		})
+	})
+	api := state.NetBoxAPI
+
+	id, err := getTenantIDFromIDOrSlug(api, "42")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+
+	id, err = getTenantIDFromIDOrSlug(api, "team-a")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+
+	_, err = getTenantIDFromIDOrSlug(api, "team-b")
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package netbox
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// timeoutTransport is a transport that aborts a single request to Netbox if
+// it does not complete within the timeout, including reading the response
+// body. Retries are sent as new requests with their own timeout.
+type timeoutTransport struct {
+	original http.RoundTripper
+	timeout  time.Duration
+}
+
+// RoundTrip sends the request with a deadline. The deadline is cancelled
+// once the response body is closed.
+func (t timeoutTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(r.Context(), t.timeout)
+
+	resp, err := t.original.RoundTrip(r.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &releasingReadCloser{ReadCloser: resp.Body, release: cancel}
+	return resp, nil
+}
@@ -0,0 +1,186 @@
+package netbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// traceRedacted replaces secret values in traced requests and responses.
+const traceRedacted = "REDACTED"
+
+// traceMaxBodySize is the number of bytes of a body that are logged.
+const traceMaxBodySize = 64 * 1024
+
+// traceSecretHeaders are the headers whose values are never logged.
+var traceSecretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+	"Set-Cookie":          true,
+}
+
+// traceSecretFields are the JSON fields whose values are never logged, e.g.
+// the key of a netbox_token or the password of a netbox_user.
+var traceSecretFields = map[string]bool{
+	"api_token": true,
+	"key":       true,
+	"password":  true,
+}
+
+// traceTransport is a transport that logs every request to Netbox and its
+// response to the Terraform log, with secrets redacted.
+type traceTransport struct {
+	original http.RoundTripper
+	// secretHeaders are additional headers whose values are redacted, e.g.
+	// the custom headers configured for the provider.
+	secretHeaders map[string]bool
+	// log is the tflog function used, which determines the log level.
+	log func(ctx context.Context, msg string, additionalFields ...map[string]interface{})
+}
+
+// isTFLogDebugEnabled returns true if Terraform logs debug messages of
+// providers.
+func isTFLogDebugEnabled() bool {
+	for _, env := range []string{"TF_LOG", "TF_LOG_PROVIDER"} {
+		switch strings.ToUpper(os.Getenv(env)) {
+		case "TRACE", "DEBUG", "JSON":
+			return true
+		}
+	}
+	return false
+}
+
+// RoundTrip logs the request, sends it and logs the response.
+func (t traceTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+
+	requestFields := map[string]interface{}{
+		"method":  r.Method,
+		"url":     r.URL.String(),
+		"headers": t.redactHeaders(r.Header),
+	}
+	if r.GetBody != nil && isJSONContentType(r.Header.Get("Content-Type")) {
+		if body, err := r.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			requestFields["body"] = redactJSONBody(data)
+		}
+	}
+	t.log(ctx, "Sending request to Netbox", requestFields)
+
+	start := time.Now()
+	resp, err := t.original.RoundTrip(r)
+	duration := time.Since(start)
+
+	responseFields := map[string]interface{}{
+		"method":      r.Method,
+		"url":         r.URL.String(),
+		"duration_ms": duration.Milliseconds(),
+	}
+	if err != nil {
+		responseFields["error"] = err.Error()
+		t.log(ctx, "Request to Netbox failed", responseFields)
+		return resp, err
+	}
+
+	responseFields["status"] = resp.StatusCode
+	responseFields["headers"] = t.redactHeaders(resp.Header)
+	if isJSONContentType(resp.Header.Get("Content-Type")) {
+		data, readErr := io.ReadAll(resp.Body)
+		// Keep closing the original body, as transports further down rely on it
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), errorReader{readErr}), resp.Body}
+		responseFields["body"] = redactJSONBody(data)
+	}
+	t.log(ctx, "Received response from Netbox", responseFields)
+
+	return resp, nil
+}
+
+// redactHeaders returns the headers as a map with the values of secret
+// headers redacted.
+func (t traceTransport) redactHeaders(header http.Header) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for key, values := range header {
+		canonical := http.CanonicalHeaderKey(key)
+		if traceSecretHeaders[canonical] || t.secretHeaders[canonical] {
+			redacted[canonical] = traceRedacted
+			continue
+		}
+		redacted[canonical] = strings.Join(values, ", ")
+	}
+	return redacted
+}
+
+// redactJSONBody returns the body with the values of all secret fields
+// redacted. Bodies that are not valid JSON are not logged, as they cannot be
+// redacted reliably.
+func redactJSONBody(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return "(body is not valid JSON)"
+	}
+
+	redacted, err := json.Marshal(redactJSONValue(body))
+	if err != nil {
+		return "(body is not valid JSON)"
+	}
+	if len(redacted) > traceMaxBodySize {
+		return string(redacted[:traceMaxBodySize]) + "...(truncated)"
+	}
+	return string(redacted)
+}
+
+func redactJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if traceSecretFields[strings.ToLower(key)] {
+				redacted[key] = traceRedacted
+			} else {
+				redacted[key] = redactJSONValue(item)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactJSONValue(item)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// errorReader returns err once the body read before it is exhausted, so that
+// an error while reading the original body is not lost.
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
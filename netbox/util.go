@@ -1,7 +1,9 @@
 package netbox
 
 import (
+	"regexp"
 	"strconv"
+	"strings"
 
 	sp "github.com/davecgh/go-spew/spew"
 )
@@ -30,3 +32,15 @@ func int64ToPtr(i int64) *int64 {
 func float64ToPtr(i float64) *float64 {
 	return &i
 }
+
+var slugInvalidCharacters = regexp.MustCompile("[^a-z0-9_]+")
+
+// slugify derives a Netbox compatible slug from a name, e.g. "Managed by Terraform" becomes "managed-by-terraform".
+func slugify(name string) string {
+	slug := slugInvalidCharacters.ReplaceAllString(strings.ToLower(name), "-")
+	slug = strings.Trim(slug, "-")
+	if len(slug) > 100 {
+		slug = strings.TrimRight(slug[:100], "-")
+	}
+	return slug
+}
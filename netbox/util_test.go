@@ -0,0 +1,16 @@
+package netbox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+
+	assert.Equal(t, "managed-by-terraform", slugify("Managed by Terraform"))
+	assert.Equal(t, "team_a-prod", slugify("  Team_A / Prod!"))
+	assert.Equal(t, "foo", slugify("foo"))
+	assert.Len(t, slugify(strings.Repeat("a", 120)), 100)
+}
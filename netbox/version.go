@@ -0,0 +1,111 @@
+package netbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// supportedNetboxVersions is the range of Netbox versions the provider is
+// tested against. Patch releases within the range are considered supported.
+const supportedNetboxVersions = ">= 3.1.1, < 3.2.0"
+
+// netboxCapability describes a feature of Netbox that is only available in
+// some versions.
+type netboxCapability struct {
+	// constraint is the range of Netbox versions providing the capability.
+	constraint string
+	// description names the feature in diagnostics.
+	description string
+}
+
+// Capabilities consulted by resources at plan time.
+const (
+	capabilitySiteASN         = "site_asn"
+	capabilityInterfaceBridge = "interface_bridge"
+)
+
+// netboxCapabilities is the registry of all known capabilities.
+var netboxCapabilities = map[string]netboxCapability{
+	capabilitySiteASN: {
+		constraint:  "< 3.2.0",
+		description: "The asn field of sites",
+	},
+	capabilityInterfaceBridge: {
+		constraint:  ">= 3.2.0",
+		description: "The bridge field of interfaces",
+	},
+}
+
+// hasNetboxCapability returns nil if the given Netbox version provides the
+// capability, or an error explaining which versions do. An unknown version,
+// e.g. if the version check was skipped, is assumed to provide every
+// capability.
+func hasNetboxCapability(version *semver.Version, capability string) error {
+	c, ok := netboxCapabilities[capability]
+	if !ok {
+		return fmt.Errorf("unknown Netbox capability %q", capability)
+	}
+	if version == nil {
+		return nil
+	}
+
+	constraint, err := semver.NewConstraint(c.constraint)
+	if err != nil {
+		return err
+	}
+	if !constraint.Check(version) {
+		return fmt.Errorf("%s requires Netbox %s, but the server runs Netbox v%s", c.description, c.constraint, version)
+	}
+	return nil
+}
+
+// getNetboxVersionFromStatus returns the version in a response of the status
+// endpoint of Netbox.
+func getNetboxVersionFromStatus(payload interface{}) (string, error) {
+	status, ok := payload.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("unexpected status response from Netbox: %v", payload)
+	}
+	version, ok := status["netbox-version"].(string)
+	if !ok {
+		return "", fmt.Errorf("status response from Netbox lacks netbox-version: %v", payload)
+	}
+	return version, nil
+}
+
+// isSupportedNetboxVersion returns true if the provider is tested against the
+// given Netbox version.
+func isSupportedNetboxVersion(version *semver.Version) bool {
+	c, err := semver.NewConstraint(supportedNetboxVersions)
+	if err != nil {
+		return false
+	}
+	return c.Check(version)
+}
+
+// requireNetboxCapability returns a CustomizeDiffFunc that fails the plan if
+// one of the given attributes is set although the Netbox version of the server
+// does not provide the capability.
+func requireNetboxCapability(capability string, attributes ...string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		api := m.(*providerState)
+
+		config := d.GetRawConfig()
+		if config.IsNull() || !config.IsKnown() {
+			return nil
+		}
+
+		for _, attribute := range attributes {
+			if config.GetAttr(attribute).IsNull() {
+				continue
+			}
+			if err := hasNetboxCapability(api.netboxVersion, capability); err != nil {
+				return fmt.Errorf("%s cannot be set: %v", attribute, err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package netbox
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSupportedNetboxVersion(t *testing.T) {
+
+	for _, tc := range []struct {
+		version  string
+		expected bool
+	}{
+		{"3.1.1", true},
+		{"3.1.11", true},
+		{"3.1.0", false},
+		{"3.2.0", false},
+		{"2.11.12", false},
+	} {
+		assert.Equal(t, tc.expected, isSupportedNetboxVersion(semver.MustParse(tc.version)), tc.version)
+	}
+}
+
+func TestHasNetboxCapability(t *testing.T) {
+
+	assert.NoError(t, hasNetboxCapability(semver.MustParse("3.1.1"), capabilitySiteASN))
+	assert.NoError(t, hasNetboxCapability(nil, capabilitySiteASN))
+
+	err := hasNetboxCapability(semver.MustParse("3.2.0"), capabilitySiteASN)
+	assert.EqualError(t, err, "The asn field of sites requires Netbox < 3.2.0, but the server runs Netbox v3.2.0")
+
+	assert.Error(t, hasNetboxCapability(semver.MustParse("3.1.1"), "unknown"))
+}
+
+func TestHasNetboxCapabilityInterfaceBridge(t *testing.T) {
+
+	assert.NoError(t, hasNetboxCapability(semver.MustParse("3.2.0"), capabilityInterfaceBridge))
+	assert.EqualError(t, hasNetboxCapability(semver.MustParse("3.1.11"), capabilityInterfaceBridge),
+		"The bridge field of interfaces requires Netbox >= 3.2.0, but the server runs Netbox v3.1.11")
+}
+
+func TestGetNetboxVersionFromStatus(t *testing.T) {
+
+	version, err := getNetboxVersionFromStatus(map[string]interface{}{"netbox-version": "3.1.9"})
+	assert.NoError(t, err)
+	assert.Equal(t, "3.1.9", version)
+
+	_, err = getNetboxVersionFromStatus(map[string]interface{}{"netbox-version": 3})
+	assert.ErrorContains(t, err, "lacks netbox-version")
+
+	_, err = getNetboxVersionFromStatus("<html>Bad Gateway</html>")
+	assert.ErrorContains(t, err, "unexpected status response from Netbox")
+}